@@ -11,15 +11,32 @@ import (
 	"strings"
 
 	"github.com/sog01/repogen/generator"
+	"github.com/sog01/repogen/parser"
 
 	_ "github.com/go-sql-driver/mysql"
 	"github.com/jmoiron/sqlx"
 	"github.com/joho/godotenv"
+	_ "github.com/lib/pq"
+	_ "github.com/mattn/go-sqlite3"
 )
 
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == "snapshot" {
+		if err := snapshot(os.Args[2:]); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
 	module := flag.String("module", "", "define go mod name")
-	tables := flag.String("tables", "", "comma separated list of tables to generate")
+	tables := flag.String("tables", "", "comma separated list of tables to generate, or * for all tables")
+	includes := flag.String("include", "", "comma separated glob or /regex/ patterns of the tables to generate")
+	excludes := flag.String("exclude", "", "comma separated glob or /regex/ patterns of the tables to skip")
+	relations := flag.String("relations", "", "comma separated relations not declared as foreign keys, e.g. orders.user_id:users.id")
+	driver := flag.String("driver", "mysql", "define db driver (mysql, postgres or sqlite3)")
+	schema := flag.String("schema", "", "define glob pattern of CREATE TABLE files to generate from instead of the db")
+	migrations := flag.String("migrations", "", "define golang-migrate or goose migrations directory to generate from instead of the db")
+	fromSnapshot := flag.String("fromSnapshot", "", "define snapshot file written by repogen snapshot to generate from instead of the db")
 	dbCreds := flag.String("creds", "", "define db credentials with dsn format")
 	dbEnv := flag.String("env", "", "define env that hold creds information")
 	dbEnvFile := flag.String("envFile", "", "define env that hold creds information")
@@ -41,6 +58,13 @@ func main() {
 
 	err := generate(*module,
 		*tables,
+		*includes,
+		*excludes,
+		*relations,
+		*driver,
+		*schema,
+		*migrations,
+		*fromSnapshot,
 		*dbCreds,
 		*dbEnv,
 		*dbEnvFile,
@@ -57,6 +81,13 @@ func main() {
 
 func generate(module,
 	tables,
+	includes,
+	excludes,
+	relations,
+	driver,
+	schema,
+	migrations,
+	fromSnapshot,
 	dbCreds,
 	dbEnv,
 	dbEnvFile,
@@ -66,46 +97,103 @@ func generate(module,
 	modelDir,
 	repositoryPackage string,
 	queryOnly bool) error {
-	if len(tables) == 0 {
-		log.Fatal("empty tables")
+	if tables == "" && includes != "" {
+		tables = "*"
+	}
+	if tables == "" {
+		return errors.New("empty tables")
 	}
 
 	if module == "" {
-		log.Fatal("empty module")
+		return errors.New("empty module")
 	}
 
 	if destination == "" {
 		destination = "./"
 	}
 
+	source, err := newSchemaSource(module,
+		driver,
+		schema,
+		migrations,
+		fromSnapshot,
+		dbCreds,
+		dbEnv,
+		dbEnvFile,
+		dbEnvPrefix)
+	if err != nil {
+		return err
+	}
+	gen := generator.NewGenerator(source, module, destination, strings.Split(tables, ","))
+	gen.SetModelPackage(modelPackage)
+	gen.SetModelDir(modelDir)
+	gen.SetRepositoryPackage(repositoryPackage)
+	gen.SetQueryOnly(queryOnly)
+	gen.SetIncludes(splitPatterns(includes))
+	gen.SetExcludes(splitPatterns(excludes))
+	gen.SetRelations(splitPatterns(relations))
+	if err := gen.Generate(); err != nil {
+		return fmt.Errorf("unable to generate repository: %v", err)
+	}
+	for _, warning := range gen.Warnings() {
+		log.Printf("warning: %s", warning)
+	}
+
+	return nil
+}
+
+func newSchemaSource(module,
+	driver,
+	schema,
+	migrations,
+	fromSnapshot,
+	dbCreds,
+	dbEnv,
+	dbEnvFile,
+	dbEnvPrefix string) (parser.SchemaSource, error) {
+	if fromSnapshot != "" {
+		return parser.NewSnapshotSource(fromSnapshot)
+	}
+
+	if schema != "" || migrations != "" {
+		dialect, err := parser.DialectFromDriver(driver)
+		if err != nil {
+			return nil, err
+		}
+		if migrations != "" {
+			return parser.NewMigrationSource(dialect, migrations)
+		}
+		return parser.NewDDLSourceFromFiles(dialect, schema)
+	}
+
 	creds := dbCreds
 	if creds == "" {
 		var err error
 		if dbEnvFile != "" {
-			creds, err = readCredsFromEnvFile(dbEnvFile, dbEnvPrefix, module)
+			creds, err = readCredsFromEnvFile(driver, dbEnvFile, dbEnvPrefix, module)
 		} else {
-			creds, err = readCredsFromEnv(dbEnv, dbEnvPrefix)
+			creds, err = readCredsFromEnv(driver, dbEnv, dbEnvPrefix)
 		}
 
 		if err != nil {
-			return err
+			return nil, err
 		}
 	}
 
-	db, err := sqlx.Open("mysql", creds)
+	db, err := sqlx.Open(driver, creds)
 	if err != nil {
-		return errors.New("unable to connect to db")
+		return nil, errors.New("unable to connect to db")
 	}
-	gen := generator.NewGenerator(db, module, destination, strings.Split(tables, ","))
-	gen.SetModelPackage(modelPackage)
-	gen.SetModelDir(modelDir)
-	gen.SetRepositoryPackage(repositoryPackage)
-	gen.SetQueryOnly(queryOnly)
-	if err := gen.Generate(); err != nil {
-		return fmt.Errorf("unable to generate repository: %v", err)
+
+	return parser.NewDBSource(db)
+}
+
+func splitPatterns(patterns string) []string {
+	if patterns == "" {
+		return nil
 	}
 
-	return nil
+	return strings.Split(patterns, ",")
 }
 
 func findModule() (string, error) {
@@ -142,7 +230,7 @@ func readModuleFromGoMod(directory string) (string, error) {
 	return moduleName, nil
 }
 
-func readCredsFromEnv(envPath, prefixenv string) (string, error) {
+func readCredsFromEnv(driver, envPath, prefixenv string) (string, error) {
 	if err := godotenv.Load(envPath); err != nil {
 		return "", errors.New("unable to load env file")
 	}
@@ -152,6 +240,22 @@ func readCredsFromEnv(envPath, prefixenv string) (string, error) {
 		prefix = prefix + "."
 	}
 
+	if driver == "sqlite3" {
+		return os.Getenv(prefix + "REPOGEN.DB.DATABASE"), nil
+	}
+
+	if driver == "postgres" {
+		dsn := fmt.Sprintf("postgres://%s:%s@%s:%s/%s", os.Getenv(prefix+"REPOGEN.DB.USERNAME"),
+			os.Getenv(prefix+"REPOGEN.DB.PASSWORD"),
+			os.Getenv(prefix+"REPOGEN.DB.HOST"),
+			os.Getenv(prefix+"REPOGEN.DB.PORT"),
+			os.Getenv(prefix+"REPOGEN.DB.DATABASE"))
+		if sslMode := os.Getenv(prefix + "REPOGEN.DB.SSLMODE"); sslMode != "" {
+			dsn += "?sslmode=" + sslMode
+		}
+		return dsn, nil
+	}
+
 	dsn := fmt.Sprintf("%s:%s@(%s:%s)/%s", os.Getenv(prefix+"REPOGEN.DB.USERNAME"),
 		os.Getenv(prefix+"REPOGEN.DB.PASSWORD"),
 		os.Getenv(prefix+"REPOGEN.DB.HOST"),
@@ -161,7 +265,7 @@ func readCredsFromEnv(envPath, prefixenv string) (string, error) {
 	return dsn, nil
 }
 
-func readCredsFromEnvFile(envFile, prefixEnv, module string) (string, error) {
+func readCredsFromEnvFile(driver, envFile, prefixEnv, module string) (string, error) {
 	rootDirPath, err := findRootDirPath(module)
 	if err != nil {
 		return "", nil
@@ -179,7 +283,7 @@ func readCredsFromEnvFile(envFile, prefixEnv, module string) (string, error) {
 		return nil
 	})
 
-	return readCredsFromEnv(envPath, prefixEnv)
+	return readCredsFromEnv(driver, envPath, prefixEnv)
 }
 
 func findRootDirPath(module string) (string, error) {
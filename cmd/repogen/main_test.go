@@ -0,0 +1,102 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+const testSchema = `
+CREATE TABLE users (id int PRIMARY KEY, email varchar(255) NOT NULL);
+CREATE TABLE orders (id int PRIMARY KEY, user_id int NOT NULL);
+CREATE TABLE schema_migrations (version bigint PRIMARY KEY);
+`
+
+// generateSchema runs generate against a temporary schema file, into a
+// destination named after the module, and returns the destination.
+func generateSchema(t *testing.T, module, tables, includes, excludes string) (string, error) {
+	t.Helper()
+	root := t.TempDir()
+	schema := filepath.Join(root, "schema.sql")
+	if err := os.WriteFile(schema, []byte(testSchema), 0644); err != nil {
+		t.Fatal(err)
+	}
+	destination := filepath.Join(root, "app")
+	if err := os.Mkdir(destination, os.ModePerm); err != nil {
+		t.Fatal(err)
+	}
+
+	return destination, generate(module, tables, includes, excludes, "", "mysql", schema, "", "",
+		"", "", "", "", destination, "", "", "", false)
+}
+
+func TestGenerateTables(t *testing.T) {
+	tests := []struct {
+		name     string
+		tables   string
+		includes string
+		excludes string
+		want     []string
+		notWant  []string
+	}{
+		{
+			name:    "listed tables",
+			tables:  "users",
+			want:    []string{"users"},
+			notWant: []string{"orders", "schema_migrations"},
+		},
+		{
+			name:     "includes without tables",
+			includes: "users,orders",
+			want:     []string{"users", "orders"},
+			notWant:  []string{"schema_migrations"},
+		},
+		{
+			name:     "all tables but the excluded",
+			tables:   "*",
+			excludes: "schema_*",
+			want:     []string{"users", "orders"},
+			notWant:  []string{"schema_migrations"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			destination, err := generateSchema(t, "example.com/app", tt.tables, tt.includes, tt.excludes)
+			if err != nil {
+				t.Fatalf("generate() error = %v", err)
+			}
+			for _, table := range tt.want {
+				if _, err := os.Stat(filepath.Join(destination, "model", table+"_gen.go")); err != nil {
+					t.Errorf("%s model is not generated: %v", table, err)
+				}
+			}
+			for _, table := range tt.notWant {
+				if _, err := os.Stat(filepath.Join(destination, "model", table+"_gen.go")); err == nil {
+					t.Errorf("%s model is generated", table)
+				}
+			}
+		})
+	}
+}
+
+func TestGenerateErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		module  string
+		tables  string
+		wantErr string
+	}{
+		{name: "empty tables", module: "example.com/app", wantErr: "empty tables"},
+		{name: "empty module", tables: "users", wantErr: "empty module"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := generateSchema(t, tt.module, tt.tables, "", "")
+			if err == nil || err.Error() != tt.wantErr {
+				t.Errorf("generate() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
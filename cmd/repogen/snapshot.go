@@ -0,0 +1,64 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"os"
+	"strings"
+
+	"github.com/sog01/repogen/parser"
+)
+
+func snapshot(args []string) error {
+	flags := flag.NewFlagSet("snapshot", flag.ExitOnError)
+	module := flags.String("module", "", "define go mod name")
+	tables := flags.String("tables", "", "comma separated list of tables to snapshot, or * for all tables")
+	includes := flags.String("include", "", "comma separated glob or /regex/ patterns of the tables to snapshot")
+	excludes := flags.String("exclude", "", "comma separated glob or /regex/ patterns of the tables to skip")
+	driver := flags.String("driver", "mysql", "define db driver (mysql, postgres or sqlite3)")
+	dbCreds := flags.String("creds", "", "define db credentials with dsn format")
+	dbEnv := flags.String("env", "", "define env that hold creds information")
+	dbEnvFile := flags.String("envFile", "", "define env that hold creds information")
+	dbEnvPrefix := flags.String("envPrefix", "", "define envPrefix that append on creds information")
+	output := flags.String("output", "repogen_snapshot.json", "define snapshot file destination")
+	flags.Parse(args)
+
+	if *module == "" {
+		*module, _ = findModule()
+	}
+	if *dbEnvFile == "" {
+		*dbEnvFile = ".env"
+	}
+	if len(*tables) == 0 {
+		return errors.New("empty tables")
+	}
+
+	source, err := newSchemaSource(*module,
+		*driver,
+		"",
+		"",
+		"",
+		*dbCreds,
+		*dbEnv,
+		*dbEnvFile,
+		*dbEnvPrefix)
+	if err != nil {
+		return err
+	}
+
+	resolvedTables, err := parser.ResolveTables(source,
+		strings.Split(*tables, ","),
+		splitPatterns(*includes),
+		splitPatterns(*excludes))
+	if err != nil {
+		return err
+	}
+
+	file, err := os.Create(*output)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	return parser.WriteSnapshot(file, source, resolvedTables)
+}
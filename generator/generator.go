@@ -1,6 +1,7 @@
 package generator
 
 import (
+	"errors"
 	"fmt"
 	"go/format"
 	"os"
@@ -9,16 +10,16 @@ import (
 
 	"github.com/sog01/repogen/parser"
 	"github.com/sog01/repogen/template"
-
-	"github.com/jmoiron/sqlx"
 )
 
 type Generator struct {
+	source      parser.SchemaSource
 	objParser   *parser.ObjectParser
 	fileGens    []*fileGen
 	module      string
 	destination string
 	tables      []string
+	warnings    []string
 	opt         *generatorOpt
 }
 
@@ -27,6 +28,9 @@ type generatorOpt struct {
 	modelDir          string
 	repositoryPackage string
 	queryOnly         bool
+	includes          []string
+	excludes          []string
+	relations         []string
 }
 
 type fileGen struct {
@@ -35,9 +39,10 @@ type fileGen struct {
 	destDir string
 }
 
-func NewGenerator(db *sqlx.DB, module, destination string, tables []string) *Generator {
+func NewGenerator(source parser.SchemaSource, module, destination string, tables []string) *Generator {
 	return &Generator{
-		objParser:   parser.NewTableParser(db),
+		source:      source,
+		objParser:   parser.NewTableParser(source),
 		module:      module,
 		tables:      tables,
 		destination: destination,
@@ -70,12 +75,56 @@ func (gen *Generator) SetQueryOnly(queryOnly bool) {
 	gen.opt.queryOnly = queryOnly
 }
 
+// SetIncludes sets the patterns of the tables to generate, see
+// parser.ResolveTables.
+func (gen *Generator) SetIncludes(includes []string) {
+	gen.opt.includes = includes
+}
+
+// SetExcludes sets the patterns of the tables to skip, see
+// parser.ResolveTables.
+func (gen *Generator) SetExcludes(excludes []string) {
+	gen.opt.excludes = excludes
+}
+
+// SetRelations sets the relations that are not declared as foreign keys on the
+// schema, formatted as table.column:referenced_table.referenced_column.
+func (gen *Generator) SetRelations(relations []string) {
+	gen.opt.relations = relations
+}
+
+// Warnings returns the warnings of the last Generate call, e.g. the tables
+// without primary key whose update and delete commands are not generated.
+func (gen *Generator) Warnings() []string {
+	return gen.warnings
+}
+
 func (gen *Generator) Generate() error {
-	for _, table := range gen.tables {
-		obj, err := gen.objParser.Parse(table)
-		if err != nil {
+	gen.warnings = nil
+	tables, err := parser.ResolveTables(gen.source, gen.tables, gen.opt.includes, gen.opt.excludes)
+	if err != nil {
+		return err
+	}
+	if len(tables) == 0 {
+		return errors.New("no tables to generate")
+	}
+
+	for _, relation := range gen.opt.relations {
+		if err := gen.addRelation(relation); err != nil {
 			return err
 		}
+	}
+
+	objs, err := gen.objParser.ParseTables(tables)
+	if err != nil {
+		return err
+	}
+
+	for _, obj := range objs {
+		if !obj.HasPrimaryKey && !gen.opt.queryOnly {
+			gen.warnings = append(gen.warnings, fmt.Sprintf("table '%s' has no primary key, Update%s and Delete%s are not generated",
+				obj.Table, obj.Name, obj.Name))
+		}
 
 		modelGen, err := gen.genModel(obj)
 		if err != nil {
@@ -97,6 +146,12 @@ func (gen *Generator) Generate() error {
 	}
 	gen.fileGens = append(gen.fileGens, repoHelperGen)
 
+	repositoriesGen, err := gen.genRepositories(objs)
+	if err != nil {
+		return err
+	}
+	gen.fileGens = append(gen.fileGens, repositoriesGen)
+
 	for _, file := range gen.fileGens {
 		destDir := gen.destination + "/" + file.destDir
 		os.Mkdir(destDir, os.ModePerm)
@@ -113,6 +168,19 @@ func (gen *Generator) Generate() error {
 	return nil
 }
 
+func (gen *Generator) addRelation(relation string) error {
+	var columns []string
+	for _, side := range strings.Split(relation, ":") {
+		columns = append(columns, strings.Split(strings.TrimSpace(side), ".")...)
+	}
+	if len(columns) != 4 {
+		return fmt.Errorf("invalid relation '%s', expected table.column:referenced_table.referenced_column", relation)
+	}
+
+	gen.objParser.AddRelation(columns[0], columns[1], columns[2], columns[3])
+	return nil
+}
+
 func (gen *Generator) resolveModelPath(modelDest string) string {
 	destinationPath, err := filepath.Abs(gen.destination)
 	if err != nil {
@@ -147,7 +215,7 @@ func reverseModelPath(modelPath []string) []string {
 }
 
 func (gen *Generator) genModel(obj *parser.Object) (*fileGen, error) {
-	tmpl := template.TemplateParser{Object: obj}
+	tmpl := template.TemplateParser{Object: obj, Dialect: gen.objParser.Dialect()}
 	modelTmpl, err := tmpl.ParseModelTmpl()
 	if err != nil {
 		return nil, err
@@ -206,13 +274,24 @@ func (gen *Generator) genRepo(obj *parser.Object, modelPath string) ([]*fileGen,
 }
 
 func (gen *Generator) genRepoQuery(obj *parser.Object, modelPath string) (*fileGen, error) {
-	tmpl := template.TemplateParser{Object: obj}
+	tmpl := template.TemplateParser{Object: obj, Dialect: gen.objParser.Dialect()}
 	var importedPackages []*template.ImportedPackage
 	for _, imported := range repositoryQueryPackages {
 		importedPackages = append(importedPackages, &template.ImportedPackage{
 			Name: imported,
 		})
 	}
+	for _, imported := range obj.QueryImportedPackages {
+		importedPackages = append(importedPackages, &template.ImportedPackage{
+			Name: imported,
+		})
+	}
+	if obj.HasPrimaryKey {
+		// the cursors are only decoded for the tables with a primary key
+		importedPackages = append(importedPackages, &template.ImportedPackage{
+			Name: "encoding/json",
+		})
+	}
 	if gen.opt.repositoryPackage != gen.opt.modelDir {
 		tmpl.ModelPackage = obj.LowerName + "model."
 		importedPackages = append(importedPackages, &template.ImportedPackage{
@@ -247,7 +326,7 @@ func (gen *Generator) genRepoQuery(obj *parser.Object, modelPath string) (*fileG
 }
 
 func (gen *Generator) genRepoCommand(obj *parser.Object, modelPath string) (*fileGen, error) {
-	tmpl := template.TemplateParser{Object: obj}
+	tmpl := template.TemplateParser{Object: obj, Dialect: gen.objParser.Dialect()}
 	var importedPackages []*template.ImportedPackage
 	for _, imported := range repositoryCommandPackages {
 		importedPackages = append(importedPackages, &template.ImportedPackage{
@@ -288,7 +367,7 @@ func (gen *Generator) genRepoCommand(obj *parser.Object, modelPath string) (*fil
 }
 
 func (gen *Generator) genRepoArgs() (*fileGen, error) {
-	tmpl := template.TemplateParser{}
+	tmpl := template.TemplateParser{Dialect: gen.objParser.Dialect()}
 	repoArgs, err := tmpl.ParseRepositoryArgs()
 	if err != nil {
 		return nil, err
@@ -327,6 +406,44 @@ func (gen *Generator) genRepoArgs() (*fileGen, error) {
 	}, nil
 }
 
+func (gen *Generator) genRepositories(objs []*parser.Object) (*fileGen, error) {
+	tmpl := template.TemplateParser{
+		Objects:   objs,
+		Dialect:   gen.objParser.Dialect(),
+		QueryOnly: gen.opt.queryOnly,
+	}
+	repositoriesTmpl, err := tmpl.ParseRepositoriesTmpl()
+	if err != nil {
+		return nil, err
+	}
+
+	var importedPackages []*template.ImportedPackage
+	for _, imported := range repositoriesPackages {
+		importedPackages = append(importedPackages, &template.ImportedPackage{
+			Name: imported,
+		})
+	}
+
+	importedTmpl, err := tmpl.ParsePackages(gen.opt.repositoryPackage, importedPackages)
+	if err != nil {
+		return nil, err
+	}
+
+	repositoriesTmpl = fmt.Sprintf(`%s
+	%s`, importedTmpl, repositoriesTmpl)
+
+	formatted, err := format.Source([]byte(repositoriesTmpl))
+	if err != nil {
+		return nil, err
+	}
+
+	return &fileGen{
+		name:    "repositories_gen.go",
+		tmpl:    string(formatted),
+		destDir: gen.opt.repositoryPackage,
+	}, nil
+}
+
 var (
 	repositoryQueryPackages = []string{
 		"context",
@@ -338,13 +455,30 @@ var (
 
 	repositoryCommandPackages = []string{
 		"context",
-		"github.com/jmoiron/sqlx",
 		"strings",
 		"database/sql",
+		"errors",
 		"fmt",
 	}
 
 	repositoryArgsPackages = []string{
+		"context",
 		"database/sql",
+		"database/sql/driver",
+		"encoding/base64",
+		"encoding/json",
+		"errors",
+		"fmt",
+		"reflect",
+		"strings",
+		"time",
+		"github.com/jmoiron/sqlx",
+	}
+
+	repositoriesPackages = []string{
+		"context",
+		"database/sql",
+		"errors",
+		"github.com/jmoiron/sqlx",
 	}
 )
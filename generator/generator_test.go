@@ -0,0 +1,331 @@
+package generator
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/sog01/repogen/parser"
+)
+
+var dialects = []string{parser.DialectMySQL, parser.DialectPostgres, parser.DialectSQLite}
+
+// testGoMod requires the packages imported by the generated code.
+const testGoMod = `module example.com/app
+
+go 1.16
+
+require (
+	github.com/guregu/null v4.0.0+incompatible
+	github.com/jmoiron/sqlx v1.3.4
+	github.com/lib/pq v1.10.4
+	github.com/mattn/go-sqlite3 v1.14.6
+	github.com/shopspring/decimal v1.3.1
+)
+`
+
+// newDDLGenerator returns a generator of every table described by the DDL,
+// writing into a temporary module directory.
+func newDDLGenerator(t *testing.T, dialect, ddl string) (*Generator, string) {
+	t.Helper()
+	source := parser.NewDDLSource(dialect)
+	if err := source.Exec(ddl); err != nil {
+		t.Fatal(err)
+	}
+
+	// the model import path is resolved from the destination named after the
+	// module
+	dir := filepath.Join(t.TempDir(), "app")
+	if err := os.Mkdir(dir, os.ModePerm); err != nil {
+		t.Fatal(err)
+	}
+	return NewGenerator(source, "example.com/app", dir, []string{"*"}), dir
+}
+
+// generateDDL generates every table described by the DDL into a temporary
+// module directory, which is returned.
+func generateDDL(t *testing.T, dialect, ddl string) string {
+	t.Helper()
+	gen, dir := newDDLGenerator(t, dialect, ddl)
+	if err := gen.Generate(); err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	return dir
+}
+
+func readGenerated(t *testing.T, dir, name string) string {
+	t.Helper()
+	content, err := os.ReadFile(filepath.Join(dir, name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(content)
+}
+
+// vetGenerated runs go vet on the generated module, which needs the packages
+// imported by the generated code from the module cache or the network. It is
+// skipped in short mode.
+func vetGenerated(t *testing.T, dir string) {
+	t.Helper()
+	if testing.Short() {
+		t.Skip("skipping go vet of the generated code in short mode")
+	}
+	if err := os.WriteFile(filepath.Join(dir, "go.mod"), []byte(testGoMod), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cmd := exec.Command("go", "vet", "./...")
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), "GOFLAGS=-mod=mod")
+	if output, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("go vet of the generated code failed: %v\n%s", err, output)
+	}
+}
+
+// testGenerated runs the given testdata test file, copied into the generated
+// repository package, against SQLite. It is skipped in short mode.
+func testGenerated(t *testing.T, dir, testFile string) {
+	t.Helper()
+	if testing.Short() {
+		t.Skip("skipping the tests of the generated code in short mode")
+	}
+	if err := os.WriteFile(filepath.Join(dir, "go.mod"), []byte(testGoMod), 0644); err != nil {
+		t.Fatal(err)
+	}
+	test, err := os.ReadFile(filepath.Join("testdata", testFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "repository", testFile), test, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cmd := exec.Command("go", "test", "./repository")
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), "GOFLAGS=-mod=mod")
+	if output, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("go test of the generated code failed: %v\n%s", err, output)
+	}
+}
+
+func TestGenerateFromDDL(t *testing.T) {
+	const ddl = `
+	CREATE TABLE users (
+		id bigint NOT NULL AUTO_INCREMENT,
+		email varchar(255) NOT NULL,
+		name varchar(100),
+		balance decimal(10,2),
+		created_at datetime NOT NULL,
+		PRIMARY KEY (id)
+	);`
+
+	for _, dialect := range dialects {
+		t.Run(dialect, func(t *testing.T) {
+			dir := generateDDL(t, dialect, ddl)
+			for _, name := range []string{
+				"model/users_gen.go",
+				"repository/users_repo_query_gen.go",
+				"repository/users_repo_command_gen.go",
+				"repository/repo_args_gen.go",
+			} {
+				if _, err := os.Stat(filepath.Join(dir, name)); err != nil {
+					t.Errorf("%s is not generated: %v", name, err)
+				}
+			}
+			vetGenerated(t, dir)
+		})
+	}
+}
+
+func TestGenerateQuotedIdentifiers(t *testing.T) {
+	const ddl = `
+	CREATE TABLE sections (
+		id int NOT NULL AUTO_INCREMENT,
+		` + "`order`" + ` int NOT NULL,
+		` + "`Title`" + ` varchar(50) NOT NULL,
+		PRIMARY KEY (id)
+	);`
+
+	tests := []struct {
+		dialect string
+		want    []string
+	}{
+		{parser.DialectMySQL, []string{"`sections`.`order`", "`Title` = ?", "INSERT INTO %s (`order`, `Title`)"}},
+		{parser.DialectPostgres, []string{`\"sections\".\"order\"`, `\"Title\" = ?`, `INSERT INTO %s (\"order\", \"Title\")`}},
+		{parser.DialectSQLite, []string{`\"sections\".\"order\"`, `\"Title\" = ?`, `INSERT INTO %s (\"order\", \"Title\")`}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.dialect, func(t *testing.T) {
+			dir := generateDDL(t, tt.dialect, ddl)
+			generated := readGenerated(t, dir, "repository/sections_repo_query_gen.go") +
+				readGenerated(t, dir, "repository/sections_repo_command_gen.go")
+			for _, want := range tt.want {
+				if !strings.Contains(generated, want) {
+					t.Errorf("generated code has no %s", want)
+				}
+			}
+			vetGenerated(t, dir)
+		})
+	}
+}
+
+func TestGeneratePostgresArrays(t *testing.T) {
+	const ddl = `
+	CREATE TABLE posts (
+		id serial PRIMARY KEY,
+		tags text[] NOT NULL,
+		scores integer ARRAY
+	);`
+
+	dir := generateDDL(t, parser.DialectPostgres, ddl)
+	model := readGenerated(t, dir, "model/posts_gen.go")
+	for _, want := range []string{"pq.StringArray", "pq.Int64Array", `"github.com/lib/pq"`} {
+		if !strings.Contains(model, want) {
+			t.Errorf("generated model has no %s", want)
+		}
+	}
+	vetGenerated(t, dir)
+}
+
+func TestGenerateWithoutPrimaryKey(t *testing.T) {
+	const ddl = `
+	CREATE TABLE users (id int PRIMARY KEY, email varchar(255) NOT NULL);
+	CREATE TABLE audit_logs (user_id int NOT NULL, action varchar(50) NOT NULL);`
+
+	gen, dir := newDDLGenerator(t, parser.DialectMySQL, ddl)
+	if err := gen.Generate(); err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+
+	want := []string{"table 'audit_logs' has no primary key, UpdateAuditLogs and DeleteAuditLogs are not generated"}
+	if got := gen.Warnings(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Warnings() = %q, want %q", got, want)
+	}
+
+	command := readGenerated(t, dir, "repository/audit_logs_repo_command_gen.go")
+	for _, notWant := range []string{"UpdateAuditLogs(", "DeleteAuditLogs("} {
+		if strings.Contains(command, notWant) {
+			t.Errorf("generated command has %s", notWant)
+		}
+	}
+	vetGenerated(t, dir)
+}
+
+func TestGenerateRelationNamedAfterField(t *testing.T) {
+	tests := []struct {
+		name string
+		ddl  string
+	}{
+		{
+			name: "column named after the relation",
+			ddl: `
+			CREATE TABLE author (id int PRIMARY KEY, name varchar(100) NOT NULL);
+			CREATE TABLE posts (
+				id int PRIMARY KEY,
+				author varchar(100),
+				author_id int REFERENCES author (id)
+			);`,
+		},
+		{
+			name: "belongs to and has many the same table",
+			ddl: `
+			CREATE TABLE user (id int PRIMARY KEY, team_id int);
+			CREATE TABLE team (id int PRIMARY KEY, owner_id int REFERENCES user (id));
+			ALTER TABLE user ADD FOREIGN KEY (team_id) REFERENCES team (id);`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			vetGenerated(t, generateDDL(t, parser.DialectMySQL, tt.ddl))
+		})
+	}
+}
+
+func TestGenerateRelationBatches(t *testing.T) {
+	const ddl = `
+	CREATE TABLE users (id INTEGER PRIMARY KEY, name TEXT NOT NULL);
+	CREATE TABLE orders (id INTEGER PRIMARY KEY, user_id INTEGER NOT NULL REFERENCES users (id));`
+
+	testGenerated(t, generateDDL(t, parser.DialectSQLite, ddl), "relation_batches_test.go")
+}
+
+func TestGenerateRelationAccessors(t *testing.T) {
+	const ddl = `
+	CREATE TABLE user (id int PRIMARY KEY, team_id int);
+	CREATE TABLE team (id int PRIMARY KEY, owner_id int REFERENCES user (id));
+	ALTER TABLE user ADD FOREIGN KEY (team_id) REFERENCES team (id);`
+
+	dir := generateDDL(t, parser.DialectMySQL, ddl)
+	query := readGenerated(t, dir, "repository/user_repo_query_gen.go")
+	for _, want := range []string{
+		"WithTeamByTeamId() RepositoryUserQuery",
+		"WithTeamByOwnerId() RepositoryUserQuery",
+		"repo.withTeamByTeamId",
+		"repo.withTeamByOwnerId",
+		"loadUserTeamByTeamId(ctx",
+		"loadUserTeamByOwnerId(ctx",
+	} {
+		if !strings.Contains(query, want) {
+			t.Errorf("generated query has no %s", want)
+		}
+	}
+}
+
+func TestGenerateCompositeKeyIndex(t *testing.T) {
+	const ddl = "CREATE TABLE settings (\n" +
+		"  user_id int NOT NULL,\n" +
+		"  version int NOT NULL,\n" +
+		"  `key` varchar(50) NOT NULL,\n" +
+		"  PRIMARY KEY (user_id, version),\n" +
+		"  UNIQUE KEY uq_key (`key`)\n" +
+		");"
+
+	dir := generateDDL(t, parser.DialectMySQL, ddl)
+	query := readGenerated(t, dir, "repository/settings_repo_query_gen.go")
+	for _, want := range []string{"GetSettingsByKey(ctx", "GetSettingsByKeyIndex(ctx"} {
+		if !strings.Contains(query, want) {
+			t.Errorf("generated query has no %s", want)
+		}
+	}
+	vetGenerated(t, dir)
+}
+
+func TestGenerateEmptyFilter(t *testing.T) {
+	const ddl = `
+	CREATE TABLE users (id INTEGER PRIMARY KEY, name TEXT NOT NULL);
+	CREATE TABLE orders (id INTEGER PRIMARY KEY, user_id INTEGER NOT NULL REFERENCES users (id));`
+
+	testGenerated(t, generateDDL(t, parser.DialectSQLite, ddl), "empty_filter_test.go")
+}
+
+func TestGenerateCursorNullableColumn(t *testing.T) {
+	const ddl = `
+	CREATE TABLE users (id INTEGER PRIMARY KEY, name TEXT NOT NULL, nickname TEXT, balance DECIMAL(10,2), seen_at DATETIME);`
+
+	testGenerated(t, generateDDL(t, parser.DialectSQLite, ddl), "cursor_nullable_test.go")
+}
+
+func TestGenerateRepositoriesInTx(t *testing.T) {
+	const ddl = `CREATE TABLE users (id INTEGER PRIMARY KEY, name TEXT NOT NULL);`
+
+	testGenerated(t, generateDDL(t, parser.DialectSQLite, ddl), "repositories_tx_test.go")
+}
+
+func TestGeneratePage(t *testing.T) {
+	const ddl = `
+	CREATE TABLE pages (id INTEGER PRIMARY KEY, title TEXT NOT NULL);
+	CREATE TABLE documents (id INTEGER PRIMARY KEY, page_id INTEGER NOT NULL REFERENCES pages (id));`
+
+	testGenerated(t, generateDDL(t, parser.DialectSQLite, ddl), "page_test.go")
+}
+
+func TestGenerateAggregates(t *testing.T) {
+	const ddl = `
+	CREATE TABLE orders (id INTEGER PRIMARY KEY, user_id INTEGER NOT NULL, qty SMALLINT NOT NULL, amount DECIMAL(10,2) NOT NULL);`
+
+	testGenerated(t, generateDDL(t, parser.DialectSQLite, ddl), "aggregate_test.go")
+}
@@ -0,0 +1,64 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jmoiron/sqlx"
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/shopspring/decimal"
+)
+
+func TestAggregates(t *testing.T) {
+	ctx := context.Background()
+	db := sqlx.MustOpen("sqlite3", ":memory:")
+	defer db.Close()
+	db.SetMaxOpenConns(1)
+	db.MustExec(`CREATE TABLE orders (id INTEGER PRIMARY KEY, user_id INTEGER NOT NULL, qty SMALLINT NOT NULL, amount DECIMAL(10,2) NOT NULL);
+	INSERT INTO orders (id, user_id, qty, amount) VALUES (1, 1, 2, 1.5), (2, 1, 3, 2.25), (3, 2, 5, 10);`)
+
+	// the integers are summed as int64 and the decimals as decimal.Decimal
+	query := NewRepoOrdersQuery(db)
+	var qty int64
+	qty, err := query.SumOrdersQty(ctx)
+	if err != nil || qty != 10 {
+		t.Errorf("SumOrdersQty() = %d, %v, want 10", qty, err)
+	}
+	var amount decimal.Decimal
+	amount, err = query.SumOrdersAmount(ctx)
+	if err != nil || !amount.Equal(decimal.RequireFromString("13.75")) {
+		t.Errorf("SumOrdersAmount() = %s, %v, want 13.75", amount, err)
+	}
+	if avg, err := query.AvgOrdersQty(ctx); err != nil || avg != 10.0/3 {
+		t.Errorf("AvgOrdersQty() = %v, %v, want %v", avg, err, 10.0/3)
+	}
+	if max, err := query.MaxOrdersQty(ctx); err != nil || max == nil || *max != 5 {
+		t.Errorf("MaxOrdersQty() = %v, %v, want 5", max, err)
+	}
+
+	filtered := NewRepoOrdersQuery(db).FilterOrders(NewOrdersFilter("AND").SetFilterByUserId(3, "="))
+	if min, err := filtered.MinOrdersQty(ctx); err != nil || min != nil {
+		t.Errorf("MinOrdersQty() of no rows = %v, %v, want nil", min, err)
+	}
+	if sum, err := filtered.SumOrdersQty(ctx); err != nil || sum != 0 {
+		t.Errorf("SumOrdersQty() of no rows = %d, %v, want 0", sum, err)
+	}
+
+	groups, err := NewRepoOrdersQuery(db).
+		GroupByOrders(NewOrdersSelectFields().UserId()).
+		SumQty().
+		SumAmount().
+		MaxQty().
+		GetOrdersGroupList(ctx)
+	if err != nil {
+		t.Fatalf("GetOrdersGroupList() error = %v", err)
+	}
+	if len(groups) != 2 {
+		t.Fatalf("GetOrdersGroupList() = %d groups, want 2", len(groups))
+	}
+	first := groups[0]
+	if first.Key.UserId != 1 || first.Count != 2 || first.SumQty != 5 ||
+		!first.SumAmount.Equal(decimal.RequireFromString("3.75")) || first.MaxQty == nil || *first.MaxQty != 3 {
+		t.Errorf("first group = user %d, %+v, want user 1 with 2 orders", first.Key.UserId, *first)
+	}
+}
@@ -0,0 +1,51 @@
+package repository
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/jmoiron/sqlx"
+	_ "github.com/mattn/go-sqlite3"
+)
+
+func TestCursorNullableColumn(t *testing.T) {
+	ctx := context.Background()
+	db := sqlx.MustOpen("sqlite3", ":memory:")
+	defer db.Close()
+	db.SetMaxOpenConns(1)
+	db.MustExec(`CREATE TABLE users (id INTEGER PRIMARY KEY, name TEXT NOT NULL, nickname TEXT, balance DECIMAL(10,2), seen_at DATETIME);
+	INSERT INTO users (id, name, nickname) VALUES (1, 'a', NULL), (2, 'b', 'bee'), (3, 'c', NULL);`)
+
+	for _, order := range []Order{NewUsersNicknameOrder().Asc(), NewUsersSeenAtOrder().Desc()} {
+		_, _, err := NewRepoUsersQuery(db).
+			OrderByUsers([]Order{order}).
+			CursorUsers(CursorPagination{Size: 2}).
+			GetUsersCursorList(ctx)
+		if err == nil || !strings.Contains(err.Error(), "is nullable") {
+			t.Errorf("GetUsersCursorList() ordered by %s error = %v, want a nullable column error", order.Value(), err)
+		}
+	}
+
+	var names []string
+	cursor := ""
+	for {
+		usersList, next, err := NewRepoUsersQuery(db).
+			OrderByUsers([]Order{NewUsersNameOrder().Desc()}).
+			CursorUsers(CursorPagination{Cursor: cursor, Size: 2}).
+			GetUsersCursorList(ctx)
+		if err != nil {
+			t.Fatalf("GetUsersCursorList() error = %v", err)
+		}
+		for _, user := range usersList {
+			names = append(names, user.Name)
+		}
+		if next == "" {
+			break
+		}
+		cursor = next
+	}
+	if got := strings.Join(names, ","); got != "c,b,a" {
+		t.Errorf("GetUsersCursorList() names = %s, want c,b,a", got)
+	}
+}
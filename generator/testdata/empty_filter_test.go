@@ -0,0 +1,80 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"example.com/app/model"
+	"github.com/jmoiron/sqlx"
+	_ "github.com/mattn/go-sqlite3"
+)
+
+func TestEmptyFilter(t *testing.T) {
+	ctx := context.Background()
+	db := sqlx.MustOpen("sqlite3", ":memory:")
+	defer db.Close()
+	db.SetMaxOpenConns(1)
+	db.MustExec(`CREATE TABLE users (id INTEGER PRIMARY KEY, name TEXT NOT NULL);
+	CREATE TABLE orders (id INTEGER PRIMARY KEY, user_id INTEGER NOT NULL REFERENCES users (id));
+	INSERT INTO users (id, name) VALUES (1, 'a'), (2, 'b');
+	INSERT INTO orders (id, user_id) VALUES (1, 1), (2, 1), (3, 2);`)
+
+	filters := map[string]Filter{
+		"empty":       NewOrdersFilter("AND"),
+		"empty and":   And(),
+		"empty or":    Or(NewOrdersFilter("AND"), And()),
+		"empty not":   Not(NewOrdersFilter("AND")),
+		"not and not": Not(And(Not(Or()))),
+	}
+	for name, filter := range filters {
+		t.Run(name, func(t *testing.T) {
+			if got := filter.Query(); got != "" {
+				t.Errorf("Query() = %q, want an empty query", got)
+			}
+
+			query := NewRepoOrdersQuery(db).FilterOrders(filter)
+			ordersList, err := query.GetOrdersList(ctx)
+			if err != nil || len(ordersList) != 3 {
+				t.Errorf("GetOrdersList() = %d orders, %v, want 3 orders", len(ordersList), err)
+			}
+			if count, err := query.GetOrdersCount(ctx); err != nil || count != 3 {
+				t.Errorf("GetOrdersCount() = %d, %v, want 3", count, err)
+			}
+			if _, _, err := query.CursorOrders(CursorPagination{Size: 2}).GetOrdersCursorList(ctx); err != nil {
+				t.Errorf("GetOrdersCursorList() error = %v", err)
+			}
+			groups, err := query.GroupByOrders(NewOrdersSelectFields().UserId()).GetOrdersGroupList(ctx)
+			if err != nil || len(groups) != 2 {
+				t.Errorf("GetOrdersGroupList() = %d groups, %v, want 2 groups", len(groups), err)
+			}
+
+			join := NewRepoOrdersQuery(db).JoinUser().FilterOrdersUserJoin(filter)
+			joinList, err := join.GetOrdersUserJoinList(ctx)
+			if err != nil || len(joinList) != 3 {
+				t.Errorf("GetOrdersUserJoinList() = %d rows, %v, want 3 rows", len(joinList), err)
+			}
+			if count, err := join.GetOrdersUserJoinCount(ctx); err != nil || count != 3 {
+				t.Errorf("GetOrdersUserJoinCount() = %d, %v, want 3", count, err)
+			}
+
+			command := NewRepoOrdersCommand(db)
+			if err := command.UpdateOrdersByFilter(ctx, &model.Orders{UserId: 2}, filter, NewOrdersSelectFields().UserId()); err == nil {
+				t.Error("UpdateOrdersByFilter() succeeded, want an error")
+			}
+			if err := command.DeleteOrdersList(ctx, filter); err == nil {
+				t.Error("DeleteOrdersList() succeeded, want an error")
+			}
+		})
+	}
+
+	command := NewRepoOrdersCommand(db)
+	if err := command.DeleteOrdersList(ctx, nil); err == nil {
+		t.Error("DeleteOrdersList(nil) succeeded, want an error")
+	}
+	if count, err := NewRepoOrdersQuery(db).FilterOrders(Not(NewOrdersFilter("AND").SetFilterByUserId(1, "="))).GetOrdersCount(ctx); err != nil || count != 1 {
+		t.Errorf("GetOrdersCount() = %d, %v, want 1", count, err)
+	}
+	if count, err := NewRepoOrdersQuery(db).GetOrdersCount(ctx); err != nil || count != 3 {
+		t.Errorf("GetOrdersCount() = %d, %v, want the 3 orders kept", count, err)
+	}
+}
@@ -0,0 +1,47 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jmoiron/sqlx"
+	_ "github.com/mattn/go-sqlite3"
+)
+
+func TestGetPage(t *testing.T) {
+	ctx := context.Background()
+	db := sqlx.MustOpen("sqlite3", ":memory:")
+	defer db.Close()
+	db.SetMaxOpenConns(1)
+	db.MustExec(`CREATE TABLE pages (id INTEGER PRIMARY KEY, title TEXT NOT NULL);
+	CREATE TABLE documents (id INTEGER PRIMARY KEY, page_id INTEGER NOT NULL REFERENCES pages (id));
+	INSERT INTO pages (id, title) VALUES (1, 'a'), (2, 'b');
+	INSERT INTO documents (id, page_id) VALUES (1, 1), (2, 1), (3, 1), (4, 1), (5, 2);`)
+
+	filter := NewDocumentsFilter("AND").SetFilterByPageId(1, "=")
+	page, err := NewRepoDocumentsQuery(db).
+		FilterDocuments(filter).
+		PaginationDocuments(PaginationData{Page: 2, Size: 3}).
+		GetDocumentsPage(ctx)
+	if err != nil {
+		t.Fatalf("GetDocumentsPage() error = %v", err)
+	}
+	if len(page.Items) != 1 || page.Total != 4 || page.Page != 2 || page.Size != 3 || page.TotalPages != 2 {
+		t.Errorf("GetDocumentsPage() = %d items, %+v, want 1 item of 4 on page 2 of 2", len(page.Items), *page)
+	}
+
+	page, err = NewRepoDocumentsQuery(db).GetDocumentsPage(ctx)
+	if err != nil {
+		t.Fatalf("GetDocumentsPage() without pagination error = %v", err)
+	}
+	if len(page.Items) != 5 || page.Total != 5 {
+		t.Errorf("GetDocumentsPage() without pagination = %d items of %d, want 5 items of 5", len(page.Items), page.Total)
+	}
+
+	// the page_id relation is named after the related table, since
+	// GetDocumentsPage is taken
+	related, err := NewRepoDocumentsQuery(db).GetDocumentsPages(ctx, page.Items[0])
+	if err != nil || related == nil || related.Id != page.Items[0].PageId {
+		t.Errorf("GetDocumentsPages() = %+v, %v, want page %d", related, err, page.Items[0].PageId)
+	}
+}
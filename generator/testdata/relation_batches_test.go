@@ -0,0 +1,53 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jmoiron/sqlx"
+	_ "github.com/mattn/go-sqlite3"
+)
+
+// relationRows exceeds the 32766 placeholders limit of SQLite.
+const relationRows = 40000
+
+func TestLoadRelationBatches(t *testing.T) {
+	ctx := context.Background()
+	db := sqlx.MustOpen("sqlite3", ":memory:")
+	defer db.Close()
+	db.SetMaxOpenConns(1)
+	db.MustExec(`CREATE TABLE users (id INTEGER PRIMARY KEY, name TEXT NOT NULL);
+	CREATE TABLE orders (id INTEGER PRIMARY KEY, user_id INTEGER NOT NULL REFERENCES users (id));`)
+
+	tx := db.MustBegin()
+	for i := 1; i <= relationRows; i++ {
+		tx.MustExec("INSERT INTO users (id, name) VALUES (?, 'user')", i)
+		tx.MustExec("INSERT INTO orders (id, user_id) VALUES (?, ?)", i, relationRows+1-i)
+	}
+	if err := tx.Commit(); err != nil {
+		t.Fatal(err)
+	}
+
+	orders, err := NewRepoOrdersQuery(db).WithUser().GetOrdersList(ctx)
+	if err != nil {
+		t.Fatalf("GetOrdersList() error = %v", err)
+	}
+	if len(orders) != relationRows {
+		t.Fatalf("GetOrdersList() returned %d orders, want %d", len(orders), relationRows)
+	}
+	for _, order := range orders {
+		if order.User == nil || order.User.Id != order.UserId {
+			t.Fatalf("order %d user = %+v, want user %d", order.Id, order.User, order.UserId)
+		}
+	}
+
+	users, err := NewRepoUsersQuery(db).WithOrders().GetUsersList(ctx)
+	if err != nil {
+		t.Fatalf("GetUsersList() error = %v", err)
+	}
+	for _, user := range users {
+		if len(user.Orders) != 1 || user.Orders[0].UserId != user.Id {
+			t.Fatalf("user %d orders = %+v, want a single order", user.Id, user.Orders)
+		}
+	}
+}
@@ -0,0 +1,52 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"example.com/app/model"
+	"github.com/jmoiron/sqlx"
+	_ "github.com/mattn/go-sqlite3"
+)
+
+func TestRepositoriesRunInTx(t *testing.T) {
+	ctx := context.Background()
+	db := sqlx.MustOpen("sqlite3", ":memory:")
+	defer db.Close()
+	db.SetMaxOpenConns(1)
+	db.MustExec(`CREATE TABLE users (id INTEGER PRIMARY KEY, name TEXT NOT NULL);`)
+
+	insert := func(r *Repositories) error {
+		_, err := r.UsersCommand.InsertUsers(ctx, &model.Users{Name: "a"})
+		return err
+	}
+	if err := NewRepositories(db).RunInTx(ctx, nil, insert); err != nil {
+		t.Fatalf("RunInTx() on a database error = %v", err)
+	}
+
+	tx := db.MustBegin()
+	if err := NewRepositories(tx).RunInTx(ctx, nil, insert); err != nil {
+		t.Fatalf("RunInTx() on a transaction error = %v", err)
+	}
+	if count, err := NewRepositories(tx).UsersQuery.GetUsersCount(ctx); err != nil || count != 2 {
+		t.Errorf("GetUsersCount() within the transaction = %d, %v, want 2", count, err)
+	}
+	if err := tx.Rollback(); err != nil {
+		t.Fatal(err)
+	}
+
+	errRollback := errors.New("rollback")
+	if err := NewRepositories(db).RunInTx(ctx, nil, func(r *Repositories) error {
+		if err := insert(r); err != nil {
+			return err
+		}
+		return errRollback
+	}); err != errRollback {
+		t.Fatalf("RunInTx() error = %v, want %v", err, errRollback)
+	}
+
+	if count, err := NewRepositories(db).UsersQuery.GetUsersCount(ctx); err != nil || count != 1 {
+		t.Errorf("GetUsersCount() = %d, %v, want the single committed user", count, err)
+	}
+}
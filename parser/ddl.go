@@ -0,0 +1,669 @@
+package parser
+
+import (
+	"database/sql"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// DDLSource describes tables from their CREATE TABLE statements, so the
+// objects could be parsed without any database connection.
+type DDLSource struct {
+	dialect string
+	tables  map[string]*TableDescribe
+}
+
+var (
+	ddlTypeModifiers = map[string]bool{
+		"precision": true,
+		"varying":   true,
+		"unsigned":  true,
+		"signed":    true,
+		"zerofill":  true,
+		"with":      true,
+		"without":   true,
+		"time":      true,
+		"zone":      true,
+	}
+
+	ddlTypeAliases = map[string]string{
+		"int2":        "smallint",
+		"int4":        "integer",
+		"int8":        "bigint",
+		"bool":        "boolean",
+		"float4":      "real",
+		"float8":      "double precision",
+		"timestamptz": "timestamp with time zone",
+		"bpchar":      "character",
+	}
+
+	ddlSerialTypes = map[string]string{
+		"smallserial": "smallint",
+		"serial2":     "smallint",
+		"serial":      "integer",
+		"serial4":     "integer",
+		"bigserial":   "bigint",
+		"serial8":     "bigint",
+	}
+
+	ddlConstraints = map[string]bool{
+		"constraint": true,
+		"primary":    true,
+		"unique":     true,
+		"key":        true,
+		"index":      true,
+		"fulltext":   true,
+		"spatial":    true,
+		"foreign":    true,
+		"check":      true,
+		"exclude":    true,
+		"like":       true,
+	}
+
+	keyRanks = map[string]int{
+		"MUL": 1,
+		"UNI": 2,
+		"PRI": 3,
+	}
+)
+
+func NewDDLSource(dialect string) *DDLSource {
+	return &DDLSource{
+		dialect: dialect,
+		tables:  make(map[string]*TableDescribe),
+	}
+}
+
+// NewDDLSourceFromFiles executes the statements of every SQL file matching the
+// given glob pattern, in lexical order.
+func NewDDLSourceFromFiles(dialect, pattern string) (*DDLSource, error) {
+	files, err := filepath.Glob(pattern)
+	if err != nil {
+		return nil, err
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no schema files match '%s'", pattern)
+	}
+
+	src := NewDDLSource(dialect)
+	for _, file := range files {
+		ddl, err := os.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+		if err := src.Exec(string(ddl)); err != nil {
+			return nil, fmt.Errorf("%s: %v", file, err)
+		}
+	}
+
+	return src, nil
+}
+
+func (src *DDLSource) Dialect() string {
+	return src.dialect
+}
+
+func (src *DDLSource) DescribeTable(table string) (*TableDescribe, error) {
+	tableDescribe, ok := src.tables[table]
+	if !ok {
+		return nil, fmt.Errorf("table '%s' not found", table)
+	}
+
+	return tableDescribe, nil
+}
+
+func (src *DDLSource) ListTables() ([]string, error) {
+	return sortedTables(src.tables), nil
+}
+
+// Exec applies the given DDL statements to the described tables. CREATE TABLE,
+// ALTER TABLE, DROP TABLE, RENAME TABLE, CREATE INDEX and DROP INDEX
+// statements are supported, the other statements are ignored.
+func (src *DDLSource) Exec(ddl string) error {
+	tokens, err := tokenizeDDL(ddl)
+	if err != nil {
+		return err
+	}
+
+	for _, statement := range splitDDLStatements(tokens) {
+		if err := src.execStatement(statement); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (src *DDLSource) execStatement(statement []ddlToken) error {
+	switch {
+	case statement[0].is("create"):
+		i := skipDDLWords(statement, 1, "or", "replace", "temporary", "temp", "unlogged", "unique")
+		if i >= len(statement) {
+			return nil
+		}
+		if statement[i].is("table") {
+			return src.createTable(statement[i+1:])
+		}
+		if statement[i].is("index") {
+			return src.createIndex(statement)
+		}
+	case statement[0].is("alter") && len(statement) > 1 && statement[1].is("table"):
+		return src.alterTable(statement[2:])
+	case statement[0].is("drop") && len(statement) > 1 && statement[1].is("table"):
+		return src.dropTable(statement[2:])
+	case statement[0].is("drop") && len(statement) > 1 && statement[1].is("index"):
+		return src.dropIndex(statement[2:])
+	case statement[0].is("rename") && len(statement) > 1 && statement[1].is("table"):
+		return src.renameTable(statement[2:])
+	}
+
+	return nil
+}
+
+func (src *DDLSource) createTable(statement []ddlToken) error {
+	i := skipDDLWords(statement, 0, "if", "not", "exists")
+	table, i := readDDLName(statement, i)
+	if table == "" {
+		return fmt.Errorf("missing table name on CREATE TABLE")
+	}
+
+	if i < len(statement) && statement[i].is("like") {
+		like, _ := readDDLName(statement, i+1)
+		return src.createTableLike(table, like)
+	}
+
+	definitions, _, err := splitDDLList(statement, i)
+	if err != nil {
+		return fmt.Errorf("table '%s': %v", table, err)
+	}
+
+	tableDescribe := &TableDescribe{Name: table}
+	var constraints [][]ddlToken
+	for _, definition := range definitions {
+		if definition[0].kind == ddlWord && ddlConstraints[strings.ToLower(definition[0].text)] {
+			constraints = append(constraints, definition)
+			continue
+		}
+
+		column, err := parseDDLColumn(definition)
+		if err != nil {
+			return fmt.Errorf("table '%s': %v", table, err)
+		}
+		tableDescribe.Columns = append(tableDescribe.Columns, column)
+		if foreignKey := parseDDLColumnForeignKey(definition); foreignKey != nil {
+			tableDescribe.ForeignKeys = append(tableDescribe.ForeignKeys, foreignKey)
+		}
+		if column.Key.String == "UNI" {
+			addDDLIndex(tableDescribe, &IndexDescribe{Unique: true, Columns: []string{column.Field.String}})
+		}
+	}
+
+	for _, constraint := range constraints {
+		if err := applyDDLConstraint(tableDescribe, constraint); err != nil {
+			return fmt.Errorf("table '%s': %v", table, err)
+		}
+	}
+	if src.dialect == DialectSQLite {
+		markSQLiteRowID(tableDescribe)
+	}
+
+	src.tables[table] = tableDescribe
+	return nil
+}
+
+func (src *DDLSource) createTableLike(table, like string) error {
+	likeDescribe, ok := src.tables[like]
+	if !ok {
+		return fmt.Errorf("table '%s': unknown table '%s'", table, like)
+	}
+
+	tableDescribe := &TableDescribe{Name: table}
+	for _, column := range likeDescribe.Columns {
+		copied := *column
+		tableDescribe.Columns = append(tableDescribe.Columns, &copied)
+	}
+	for _, index := range likeDescribe.Indexes {
+		copied := *index
+		copied.Columns = append([]string{}, index.Columns...)
+		tableDescribe.Indexes = append(tableDescribe.Indexes, &copied)
+	}
+	src.tables[table] = tableDescribe
+	return nil
+}
+
+func parseDDLColumn(definition []ddlToken) (*ColumnDescribe, error) {
+	if len(definition) < 2 {
+		return nil, fmt.Errorf("invalid column definition '%s'", ddlText(definition))
+	}
+
+	column := &ColumnDescribe{
+		Field: nullString(definition[0].text),
+		Null:  nullString("YES"),
+		Key:   nullString(""),
+		Extra: nullString(""),
+	}
+
+	typeTokens := []ddlToken{definition[1]}
+	var array bool
+	i := 2
+	for i < len(definition) {
+		token := definition[i]
+		if token.is("(") {
+			end := skipDDLGroup(definition, i)
+			typeTokens = append(typeTokens, definition[i:end]...)
+			i = end
+			continue
+		}
+		// PostgreSQL arrays, e.g. text[], integer[3] or text ARRAY
+		if token.is("[") || token.is("array") {
+			array = true
+			i = skipDDLArrayBounds(definition, i)
+			continue
+		}
+		if token.kind != ddlWord || !ddlTypeModifiers[strings.ToLower(token.text)] {
+			break
+		}
+		typeTokens = append(typeTokens, token)
+		i++
+	}
+
+	columnType := ddlText(typeTokens)
+	baseType := strings.ToLower(definition[1].text)
+	if alias, ok := ddlTypeAliases[baseType]; ok {
+		columnType = alias + columnType[len(baseType):]
+	}
+	if serialType, ok := ddlSerialTypes[baseType]; ok {
+		columnType = serialType + columnType[len(baseType):]
+		column.Extra = nullString("auto_increment")
+	}
+	if array {
+		columnType += "[]"
+	}
+	column.Type = nullString(columnType)
+
+	for i < len(definition) {
+		token := definition[i]
+		switch {
+		case token.is("not") && i+1 < len(definition) && definition[i+1].is("null"):
+			column.Null = nullString("NO")
+			i += 2
+		case token.is("null"):
+			column.Null = nullString("YES")
+			i++
+		case token.is("default"):
+			var end int
+			column.Default, end = readDDLDefault(definition, i+1)
+			if strings.HasPrefix(column.Default.String, "nextval(") {
+				column.Extra = nullString("auto_increment")
+			}
+			i = end
+		case token.is("auto_increment"), token.is("autoincrement"):
+			column.Extra = nullString("auto_increment")
+			i++
+		case token.is("identity"):
+			column.Extra = nullString("auto_increment")
+			i++
+		case token.is("primary"):
+			column.Key = nullString("PRI")
+			column.Null = nullString("NO")
+			i = skipDDLWords(definition, i+1, "key")
+		case token.is("unique"):
+			setDDLKey(column, "UNI")
+			i = skipDDLWords(definition, i+1, "key", "index")
+		case token.is("as") && i+1 < len(definition) && definition[i+1].is("("):
+			column.Extra = nullString("VIRTUAL GENERATED")
+			i++
+		case token.is("stored"):
+			column.Extra = nullString("STORED GENERATED")
+			i++
+		case token.is("on") && i+2 < len(definition) && definition[i+1].is("update"):
+			if column.Extra.String == "" {
+				column.Extra = nullString("on update " + strings.ToLower(definition[i+2].text))
+			}
+			i += 3
+		case token.is("references"):
+			_, _, i = readDDLReferences(definition, i)
+		case token.is("("):
+			i = skipDDLGroup(definition, i)
+		default:
+			i++
+		}
+	}
+
+	return column, nil
+}
+
+// readDDLDefault reads the default value expression starting at tokens[start],
+// returning the index after it.
+func readDDLDefault(tokens []ddlToken, start int) (sql.NullString, int) {
+	if start >= len(tokens) {
+		return sql.NullString{}, start
+	}
+	if tokens[start].is("null") {
+		return sql.NullString{}, start + 1
+	}
+
+	end := start + 1
+	if tokens[start].is("(") {
+		end = skipDDLGroup(tokens, start)
+	} else if end < len(tokens) && tokens[end].is("(") {
+		end = skipDDLGroup(tokens, end)
+	}
+	// cast, e.g. 'active'::character varying
+	for end+2 < len(tokens) && tokens[end].is(":") && tokens[end+1].is(":") {
+		end += 3
+		for end < len(tokens) && tokens[end].kind == ddlWord &&
+			ddlTypeModifiers[strings.ToLower(tokens[end].text)] {
+			end++
+		}
+	}
+
+	if end == start+1 && tokens[start].kind == ddlString {
+		return nullString(tokens[start].text), end
+	}
+
+	return nullString(ddlText(tokens[start:end])), end
+}
+
+// readDDLReferences reads the REFERENCES clause starting at tokens[start],
+// returning the referenced table and columns and the index after the clause.
+func readDDLReferences(tokens []ddlToken, start int) (string, []string, int) {
+	table, i := readDDLName(tokens, start+1)
+	var columns []string
+	if i < len(tokens) && tokens[i].is("(") {
+		items, end, err := splitDDLList(tokens, i)
+		if err != nil {
+			return table, nil, len(tokens)
+		}
+		for _, item := range items {
+			columns = append(columns, item[0].text)
+		}
+		i = end
+	}
+
+	for i < len(tokens) {
+		switch {
+		case tokens[i].is("match"):
+			i += 2
+		case tokens[i].is("on"):
+			i += 2
+			if i < len(tokens) && (tokens[i].is("set") || tokens[i].is("no")) {
+				i++
+			}
+			i++
+		default:
+			return table, columns, i
+		}
+	}
+
+	return table, columns, len(tokens)
+}
+
+// parseDDLColumnForeignKey returns the foreign key of the inline REFERENCES
+// clause of the column definition, if any.
+func parseDDLColumnForeignKey(definition []ddlToken) *ForeignKeyDescribe {
+	for i := 2; i < len(definition); i++ {
+		if definition[i].kind != ddlWord || !definition[i].is("references") {
+			continue
+		}
+
+		table, columns, _ := readDDLReferences(definition, i)
+		return &ForeignKeyDescribe{
+			Columns:           []string{definition[0].text},
+			ReferencedTable:   table,
+			ReferencedColumns: columns,
+		}
+	}
+
+	return nil
+}
+
+// skipDDLArrayBounds skips the ARRAY keyword and the brackets of the array
+// dimensions starting at tokens[start], returning the index after them.
+func skipDDLArrayBounds(tokens []ddlToken, start int) int {
+	i := skipDDLWords(tokens, start, "array")
+	for i < len(tokens) && tokens[i].is("[") {
+		for i < len(tokens) && !tokens[i].is("]") {
+			i++
+		}
+		i++
+	}
+
+	return i
+}
+
+// skipDDLGroup returns the index after the parenthesized group starting at
+// tokens[start].
+func skipDDLGroup(tokens []ddlToken, start int) int {
+	_, end, err := splitDDLList(tokens, start)
+	if err != nil {
+		return len(tokens)
+	}
+
+	return end
+}
+
+func applyDDLConstraint(tableDescribe *TableDescribe, constraint []ddlToken) error {
+	var (
+		i    int
+		name string
+	)
+	if constraint[0].is("constraint") && len(constraint) > 1 {
+		i, name = 2, constraint[1].text
+	}
+	if i >= len(constraint) {
+		return nil
+	}
+
+	var (
+		key     string
+		indexed bool
+	)
+	switch {
+	case constraint[i].is("foreign"):
+		return applyDDLForeignKey(tableDescribe, name, constraint[i:])
+	case constraint[i].is("primary"):
+		key = "PRI"
+	case constraint[i].is("unique"):
+		key, indexed = "UNI", true
+	case constraint[i].is("key"), constraint[i].is("index"):
+		key, indexed = "MUL", true
+	case constraint[i].is("fulltext"), constraint[i].is("spatial"):
+		key = "MUL"
+	default:
+		return nil
+	}
+
+	columns, err := readDDLIndexColumns(constraint, i)
+	if err != nil {
+		return err
+	}
+	if err := applyDDLKey(tableDescribe, key, columns); err != nil {
+		return err
+	}
+
+	if indexed {
+		// MySQL index name, e.g. UNIQUE KEY uq_email (email)
+		j := skipDDLWords(constraint, i, "unique", "key", "index")
+		if name == "" && j < len(constraint) && !constraint[j].is("(") && !constraint[j].is("using") {
+			name = constraint[j].text
+		}
+		addDDLIndex(tableDescribe, &IndexDescribe{
+			Name:    name,
+			Unique:  key == "UNI",
+			Columns: columns,
+		})
+	}
+	return nil
+}
+
+func addDDLIndex(tableDescribe *TableDescribe, index *IndexDescribe) {
+	for _, column := range index.Columns {
+		if findDDLColumn(tableDescribe, column) == nil {
+			return
+		}
+	}
+
+	tableDescribe.Indexes = append(tableDescribe.Indexes, index)
+}
+
+func dropDDLIndex(tableDescribe *TableDescribe, dropped func(*IndexDescribe) bool) {
+	var indexes []*IndexDescribe
+	for _, index := range tableDescribe.Indexes {
+		if !dropped(index) {
+			indexes = append(indexes, index)
+		}
+	}
+	tableDescribe.Indexes = indexes
+}
+
+func applyDDLForeignKey(tableDescribe *TableDescribe, name string, constraint []ddlToken) error {
+	columns, err := readDDLIndexColumns(constraint, 0)
+	if err != nil {
+		return err
+	}
+	for _, column := range columns {
+		if findDDLColumn(tableDescribe, column) == nil {
+			return fmt.Errorf("unknown column '%s'", column)
+		}
+	}
+
+	for i, token := range constraint {
+		if token.kind != ddlWord || !token.is("references") {
+			continue
+		}
+
+		table, referencedColumns, _ := readDDLReferences(constraint, i)
+		tableDescribe.ForeignKeys = append(tableDescribe.ForeignKeys, &ForeignKeyDescribe{
+			Name:              name,
+			Columns:           columns,
+			ReferencedTable:   table,
+			ReferencedColumns: referencedColumns,
+		})
+		return nil
+	}
+
+	return fmt.Errorf("missing REFERENCES on '%s'", ddlText(constraint))
+}
+
+// applyDDLKey sets the key of the indexed columns the same way MySQL reports
+// them on DESCRIBE: every primary key column is PRI, a single column unique
+// index is UNI and the first column of the other indexes is MUL.
+func applyDDLKey(tableDescribe *TableDescribe, key string, columns []string) error {
+	if len(columns) > 1 && key == "UNI" {
+		key = "MUL"
+	}
+
+	for index, name := range columns {
+		column := findDDLColumn(tableDescribe, name)
+		if column == nil {
+			return fmt.Errorf("unknown column '%s'", name)
+		}
+
+		switch {
+		case key == "PRI":
+			column.Key = nullString(key)
+			column.Null = nullString("NO")
+		case index == 0:
+			setDDLKey(column, key)
+		}
+	}
+
+	return nil
+}
+
+// readDDLIndexColumns reads the column names of the first parenthesized list
+// found from tokens[start].
+func readDDLIndexColumns(tokens []ddlToken, start int) ([]string, error) {
+	for i := start; i < len(tokens); i++ {
+		if !tokens[i].is("(") {
+			continue
+		}
+
+		items, _, err := splitDDLList(tokens, i)
+		if err != nil {
+			return nil, err
+		}
+
+		var columns []string
+		for _, item := range items {
+			columns = append(columns, item[0].text)
+		}
+		return columns, nil
+	}
+
+	return nil, fmt.Errorf("missing index columns on '%s'", ddlText(tokens))
+}
+
+// markSQLiteRowID marks a single INTEGER PRIMARY KEY column as auto increment,
+// since SQLite makes it an alias of the rowid.
+func markSQLiteRowID(tableDescribe *TableDescribe) {
+	var primaryKeys []*ColumnDescribe
+	for _, column := range tableDescribe.Columns {
+		if column.Key.String == "PRI" {
+			primaryKeys = append(primaryKeys, column)
+		}
+	}
+
+	if len(primaryKeys) == 1 && primaryKeys[0].Type.String == "integer" {
+		primaryKeys[0].Extra = nullString("auto_increment")
+	}
+}
+
+func findDDLColumn(tableDescribe *TableDescribe, name string) *ColumnDescribe {
+	index := findDDLColumnIndex(tableDescribe, name)
+	if index == -1 {
+		return nil
+	}
+
+	return tableDescribe.Columns[index]
+}
+
+func setDDLKey(column *ColumnDescribe, key string) {
+	if keyRanks[key] > keyRanks[column.Key.String] {
+		column.Key = nullString(key)
+	}
+}
+
+// readDDLName reads a possibly schema qualified name starting at tokens[start],
+// returning the unqualified name and the index after it.
+func readDDLName(tokens []ddlToken, start int) (string, int) {
+	if start >= len(tokens) {
+		return "", start
+	}
+
+	name := tokens[start].text
+	i := start + 1
+	for i+1 < len(tokens) && tokens[i].is(".") {
+		name = tokens[i+1].text
+		i += 2
+	}
+
+	return name, i
+}
+
+// skipDDLWords returns the index of the first token from tokens[start] that is
+// not one of the given keywords.
+func skipDDLWords(tokens []ddlToken, start int, words ...string) int {
+	i := start
+	for ; i < len(tokens); i++ {
+		var skipped bool
+		for _, word := range words {
+			if tokens[i].is(word) {
+				skipped = true
+				break
+			}
+		}
+		if !skipped {
+			break
+		}
+	}
+
+	return i
+}
+
+func nullString(s string) sql.NullString {
+	return sql.NullString{String: s, Valid: true}
+}
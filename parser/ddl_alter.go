@@ -0,0 +1,432 @@
+package parser
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+)
+
+func (src *DDLSource) createIndex(statement []ddlToken) error {
+	var unique bool
+	i := 1
+	for ; i < len(statement) && !statement[i].is("index"); i++ {
+		if statement[i].is("unique") {
+			unique = true
+		}
+	}
+
+	i = skipDDLWords(statement, i+1, "concurrently", "if", "not", "exists")
+	var name string
+	if i < len(statement) && !statement[i].is("on") {
+		name, i = readDDLName(statement, i)
+	}
+	if i >= len(statement) || !statement[i].is("on") {
+		return fmt.Errorf("missing table name on CREATE INDEX")
+	}
+
+	table, i := readDDLName(statement, skipDDLWords(statement, i+1, "only"))
+	tableDescribe, err := src.DescribeTable(table)
+	if err != nil {
+		return err
+	}
+
+	columns, err := readDDLIndexColumns(statement, i)
+	if err != nil {
+		return err
+	}
+	for _, column := range columns {
+		// expression indexes do not set any column key
+		if findDDLColumn(tableDescribe, column) == nil {
+			return nil
+		}
+	}
+
+	key := "MUL"
+	if unique {
+		key = "UNI"
+	}
+	if err := applyDDLKey(tableDescribe, key, columns); err != nil {
+		return err
+	}
+
+	// partial indexes do not get finders
+	for _, token := range statement[i:] {
+		if token.kind == ddlWord && token.is("where") {
+			return nil
+		}
+	}
+	addDDLIndex(tableDescribe, &IndexDescribe{
+		Name:    name,
+		Unique:  unique,
+		Columns: columns,
+	})
+	return nil
+}
+
+func (src *DDLSource) dropIndex(statement []ddlToken) error {
+	i := skipDDLWords(statement, 0, "concurrently", "if", "exists")
+	for _, item := range splitDDLTopLevel(statement[i:]) {
+		name, j := readDDLName(item, 0)
+		for _, tableDescribe := range src.tables {
+			// MySQL DROP INDEX name ON table
+			if j+1 < len(item) && item[j].is("on") {
+				if table, _ := readDDLName(item, j+1); table != tableDescribe.Name {
+					continue
+				}
+			}
+			dropDDLIndex(tableDescribe, func(index *IndexDescribe) bool {
+				return strings.EqualFold(index.Name, name)
+			})
+		}
+	}
+
+	return nil
+}
+
+func (src *DDLSource) alterTable(statement []ddlToken) error {
+	i := skipDDLWords(statement, 0, "if", "exists", "only")
+	table, i := readDDLName(statement, i)
+	tableDescribe, err := src.DescribeTable(table)
+	if err != nil {
+		return err
+	}
+
+	for _, action := range splitDDLTopLevel(statement[i:]) {
+		if len(action) == 0 {
+			continue
+		}
+		if err := src.alterTableAction(tableDescribe, action); err != nil {
+			return fmt.Errorf("table '%s': %v", table, err)
+		}
+	}
+
+	return nil
+}
+
+func (src *DDLSource) alterTableAction(tableDescribe *TableDescribe, action []ddlToken) error {
+	switch {
+	case action[0].is("add"):
+		return addDDLColumn(tableDescribe, action)
+	case action[0].is("drop"):
+		return dropDDLColumn(tableDescribe, action)
+	case action[0].is("modify"):
+		i := skipDDLWords(action, 1, "column")
+		column, err := parseDDLColumn(action[i:])
+		if err != nil {
+			return err
+		}
+		return replaceDDLColumn(tableDescribe, column.Field.String, column)
+	case action[0].is("change"):
+		i := skipDDLWords(action, 1, "column")
+		if i+1 >= len(action) {
+			return fmt.Errorf("invalid CHANGE COLUMN '%s'", ddlText(action))
+		}
+		column, err := parseDDLColumn(action[i+1:])
+		if err != nil {
+			return err
+		}
+		if err := replaceDDLColumn(tableDescribe, action[i].text, column); err != nil {
+			return err
+		}
+		src.renameDDLColumn(tableDescribe.Name, action[i].text, column.Field.String)
+		return nil
+	case action[0].is("alter"):
+		return alterDDLColumn(tableDescribe, action)
+	case action[0].is("rename"):
+		return src.renameDDLObject(tableDescribe, action)
+	}
+
+	return nil
+}
+
+func addDDLColumn(tableDescribe *TableDescribe, action []ddlToken) error {
+	i := skipDDLWords(action, 1, "column")
+	i = skipDDLWords(action, i, "if", "not", "exists")
+	if i >= len(action) {
+		return fmt.Errorf("invalid ADD '%s'", ddlText(action))
+	}
+
+	definition := action[i:]
+	if definition[0].kind == ddlWord && ddlConstraints[strings.ToLower(definition[0].text)] {
+		return applyDDLConstraint(tableDescribe, definition)
+	}
+
+	column, err := parseDDLColumn(definition)
+	if err != nil {
+		return err
+	}
+	if findDDLColumn(tableDescribe, column.Field.String) != nil {
+		return nil
+	}
+	if foreignKey := parseDDLColumnForeignKey(definition); foreignKey != nil {
+		tableDescribe.ForeignKeys = append(tableDescribe.ForeignKeys, foreignKey)
+	}
+
+	// MySQL column position, i.e. FIRST or AFTER column
+	position := len(tableDescribe.Columns)
+	n := len(definition)
+	switch {
+	case definition[n-1].is("first"):
+		position = 0
+	case n > 2 && definition[n-2].is("after"):
+		after := findDDLColumnIndex(tableDescribe, definition[n-1].text)
+		if after == -1 {
+			return fmt.Errorf("unknown column '%s'", definition[n-1].text)
+		}
+		position = after + 1
+	}
+
+	columns := append([]*ColumnDescribe{}, tableDescribe.Columns[:position]...)
+	columns = append(columns, column)
+	tableDescribe.Columns = append(columns, tableDescribe.Columns[position:]...)
+	if column.Key.String == "UNI" {
+		addDDLIndex(tableDescribe, &IndexDescribe{Unique: true, Columns: []string{column.Field.String}})
+	}
+	return nil
+}
+
+func dropDDLColumn(tableDescribe *TableDescribe, action []ddlToken) error {
+	if len(action) < 2 {
+		return fmt.Errorf("invalid DROP '%s'", ddlText(action))
+	}
+
+	if action[1].is("primary") {
+		for _, column := range tableDescribe.Columns {
+			if column.Key.String == "PRI" {
+				column.Key = nullString("")
+			}
+		}
+		return nil
+	}
+	if action[1].is("foreign") || action[1].is("constraint") {
+		// MySQL DROP FOREIGN KEY name or DROP CONSTRAINT [IF EXISTS] name
+		i := skipDDLWords(action, 1, "foreign", "key", "constraint", "if", "exists")
+		if i < len(action) {
+			dropDDLForeignKey(tableDescribe, func(foreignKey *ForeignKeyDescribe) bool {
+				return strings.EqualFold(foreignKey.Name, action[i].text)
+			})
+		}
+		return nil
+	}
+	if action[1].is("index") || action[1].is("key") {
+		i := skipDDLWords(action, 1, "index", "key", "if", "exists")
+		if i < len(action) {
+			dropDDLIndex(tableDescribe, func(index *IndexDescribe) bool {
+				return strings.EqualFold(index.Name, action[i].text)
+			})
+		}
+		return nil
+	}
+	if action[1].kind == ddlWord && ddlConstraints[strings.ToLower(action[1].text)] {
+		return nil
+	}
+
+	i := skipDDLWords(action, 1, "column")
+	i = skipDDLWords(action, i, "if", "exists")
+	if i >= len(action) {
+		return fmt.Errorf("invalid DROP '%s'", ddlText(action))
+	}
+
+	index := findDDLColumnIndex(tableDescribe, action[i].text)
+	if index == -1 {
+		return nil
+	}
+	tableDescribe.Columns = append(tableDescribe.Columns[:index], tableDescribe.Columns[index+1:]...)
+	dropDDLForeignKey(tableDescribe, func(foreignKey *ForeignKeyDescribe) bool {
+		return containsDDLColumn(foreignKey.Columns, action[i].text)
+	})
+	dropDDLIndex(tableDescribe, func(index *IndexDescribe) bool {
+		return containsDDLColumn(index.Columns, action[i].text)
+	})
+	return nil
+}
+
+func containsDDLColumn(columns []string, name string) bool {
+	for _, column := range columns {
+		if strings.EqualFold(column, name) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func dropDDLForeignKey(tableDescribe *TableDescribe, dropped func(*ForeignKeyDescribe) bool) {
+	var foreignKeys []*ForeignKeyDescribe
+	for _, foreignKey := range tableDescribe.ForeignKeys {
+		if !dropped(foreignKey) {
+			foreignKeys = append(foreignKeys, foreignKey)
+		}
+	}
+	tableDescribe.ForeignKeys = foreignKeys
+}
+
+// replaceDDLColumn replaces the named column keeping its position and key,
+// since its indexes are kept. A primary key column stays NOT NULL.
+func replaceDDLColumn(tableDescribe *TableDescribe, name string, column *ColumnDescribe) error {
+	index := findDDLColumnIndex(tableDescribe, name)
+	if index == -1 {
+		return fmt.Errorf("unknown column '%s'", name)
+	}
+
+	setDDLKey(column, tableDescribe.Columns[index].Key.String)
+	if column.Key.String == "PRI" {
+		column.Null = nullString("NO")
+	}
+	tableDescribe.Columns[index] = column
+	return nil
+}
+
+func alterDDLColumn(tableDescribe *TableDescribe, action []ddlToken) error {
+	i := skipDDLWords(action, 1, "column")
+	if i+1 >= len(action) {
+		return fmt.Errorf("invalid ALTER COLUMN '%s'", ddlText(action))
+	}
+
+	column := findDDLColumn(tableDescribe, action[i].text)
+	if column == nil {
+		return fmt.Errorf("unknown column '%s'", action[i].text)
+	}
+
+	name, change := action[i], action[i+1:]
+	switch {
+	case change[0].is("set") && len(change) > 2 && change[1].is("not"):
+		column.Null = nullString("NO")
+	case change[0].is("drop") && len(change) > 2 && change[1].is("not"):
+		column.Null = nullString("YES")
+	case change[0].is("set") && len(change) > 1 && change[1].is("default"):
+		column.Default, _ = readDDLDefault(change, 2)
+	case change[0].is("drop") && len(change) > 1 && change[1].is("default"):
+		column.Default = sql.NullString{}
+	case change[0].is("drop") && len(change) > 1 && change[1].is("identity"):
+		column.Extra = nullString("")
+	case change[0].is("add") && len(change) > 1 && change[1].is("generated"):
+		column.Extra = nullString("auto_increment")
+	case change[0].is("type"), change[0].is("set") && len(change) > 2 && change[1].is("data"):
+		j := skipDDLWords(change, 0, "set", "data", "type")
+		changed, err := parseDDLColumn(append([]ddlToken{name}, change[j:]...))
+		if err != nil {
+			return err
+		}
+		column.Type = changed.Type
+	}
+
+	return nil
+}
+
+func (src *DDLSource) renameDDLObject(tableDescribe *TableDescribe, action []ddlToken) error {
+	switch {
+	case len(action) < 3:
+		return fmt.Errorf("invalid RENAME '%s'", ddlText(action))
+	case action[1].is("to"), action[1].is("as"):
+		name, _ := readDDLName(action, 2)
+		return src.renameDDLTable(tableDescribe.Name, name)
+	case action[1].is("index"), action[1].is("key"):
+		if len(action) > 4 && action[3].is("to") {
+			for _, index := range tableDescribe.Indexes {
+				if strings.EqualFold(index.Name, action[2].text) {
+					index.Name = action[4].text
+				}
+			}
+		}
+		return nil
+	case action[1].is("constraint"):
+		return nil
+	}
+
+	i := skipDDLWords(action, 1, "column")
+	if i+2 >= len(action) || !action[i+1].is("to") {
+		return fmt.Errorf("invalid RENAME '%s'", ddlText(action))
+	}
+
+	column := findDDLColumn(tableDescribe, action[i].text)
+	if column == nil {
+		return fmt.Errorf("unknown column '%s'", action[i].text)
+	}
+	column.Field = nullString(action[i+2].text)
+	src.renameDDLColumn(tableDescribe.Name, action[i].text, action[i+2].text)
+	return nil
+}
+
+// renameDDLColumn renames the column in the indexes and foreign keys of the
+// table and in the foreign keys referencing it.
+func (src *DDLSource) renameDDLColumn(table, from, to string) {
+	rename := func(columns []string) {
+		for i := range columns {
+			if strings.EqualFold(columns[i], from) {
+				columns[i] = to
+			}
+		}
+	}
+
+	for _, tableDescribe := range src.tables {
+		if tableDescribe.Name == table {
+			for _, index := range tableDescribe.Indexes {
+				rename(index.Columns)
+			}
+		}
+		for _, foreignKey := range tableDescribe.ForeignKeys {
+			if tableDescribe.Name == table {
+				rename(foreignKey.Columns)
+			}
+			if foreignKey.ReferencedTable == table {
+				rename(foreignKey.ReferencedColumns)
+			}
+		}
+	}
+}
+
+func (src *DDLSource) dropTable(statement []ddlToken) error {
+	i := skipDDLWords(statement, 0, "if", "exists")
+	for _, item := range splitDDLTopLevel(statement[i:]) {
+		table, _ := readDDLName(item, 0)
+		delete(src.tables, table)
+	}
+
+	return nil
+}
+
+func (src *DDLSource) renameTable(statement []ddlToken) error {
+	for _, item := range splitDDLTopLevel(statement) {
+		from, i := readDDLName(item, 0)
+		if i >= len(item) || !item[i].is("to") {
+			return fmt.Errorf("invalid RENAME TABLE '%s'", ddlText(item))
+		}
+
+		to, _ := readDDLName(item, i+1)
+		if err := src.renameDDLTable(from, to); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (src *DDLSource) renameDDLTable(from, to string) error {
+	tableDescribe, err := src.DescribeTable(from)
+	if err != nil {
+		return err
+	}
+
+	delete(src.tables, from)
+	tableDescribe.Name = to
+	src.tables[to] = tableDescribe
+	for _, other := range src.tables {
+		for _, foreignKey := range other.ForeignKeys {
+			if foreignKey.ReferencedTable == from {
+				foreignKey.ReferencedTable = to
+			}
+		}
+	}
+	return nil
+}
+
+func findDDLColumnIndex(tableDescribe *TableDescribe, name string) int {
+	for index, column := range tableDescribe.Columns {
+		if strings.EqualFold(column.Field.String, name) {
+			return index
+		}
+	}
+
+	return -1
+}
@@ -0,0 +1,215 @@
+package parser
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDDLSourceAlterTable(t *testing.T) {
+	const schema = `
+	CREATE TABLE pages (id int PRIMARY KEY);
+	CREATE TABLE users (
+		id int NOT NULL AUTO_INCREMENT,
+		name varchar(10),
+		page_id int,
+		PRIMARY KEY (id),
+		CONSTRAINT fk_page FOREIGN KEY (page_id) REFERENCES pages (id)
+	);`
+
+	tests := []struct {
+		name        string
+		ddl         string
+		table       string
+		columns     []string
+		indexes     []*IndexDescribe
+		foreignKeys []*ForeignKeyDescribe
+		missing     []string
+	}{
+		{
+			name:  "modify primary key keeps it not null",
+			ddl:   `ALTER TABLE users MODIFY id BIGINT UNSIGNED AUTO_INCREMENT;`,
+			table: "users",
+			columns: []string{
+				"id|bigint unsigned|NO|PRI||auto_increment",
+				"name|varchar(10)|YES|||",
+				"page_id|int|YES|||",
+			},
+			foreignKeys: []*ForeignKeyDescribe{
+				{Name: "fk_page", Columns: []string{"page_id"}, ReferencedTable: "pages", ReferencedColumns: []string{"id"}},
+			},
+		},
+		{
+			name:  "change column renames the foreign key column",
+			ddl:   `ALTER TABLE users CHANGE COLUMN page_id home_page_id bigint NOT NULL;`,
+			table: "users",
+			columns: []string{
+				"id|int|NO|PRI||auto_increment",
+				"name|varchar(10)|YES|||",
+				"home_page_id|bigint|NO|||",
+			},
+			foreignKeys: []*ForeignKeyDescribe{
+				{Name: "fk_page", Columns: []string{"home_page_id"}, ReferencedTable: "pages", ReferencedColumns: []string{"id"}},
+			},
+		},
+		{
+			name: "add columns at positions",
+			ddl: `ALTER TABLE users ADD COLUMN email varchar(50) NOT NULL UNIQUE AFTER id,
+				ADD created_at datetime FIRST;`,
+			table: "users",
+			columns: []string{
+				"created_at|datetime|YES|||",
+				"id|int|NO|PRI||auto_increment",
+				"email|varchar(50)|NO|UNI||",
+				"name|varchar(10)|YES|||",
+				"page_id|int|YES|||",
+			},
+			indexes: []*IndexDescribe{{Unique: true, Columns: []string{"email"}}},
+			foreignKeys: []*ForeignKeyDescribe{
+				{Name: "fk_page", Columns: []string{"page_id"}, ReferencedTable: "pages", ReferencedColumns: []string{"id"}},
+			},
+		},
+		{
+			name:  "drop column drops its foreign key",
+			ddl:   `ALTER TABLE users DROP COLUMN page_id;`,
+			table: "users",
+			columns: []string{
+				"id|int|NO|PRI||auto_increment",
+				"name|varchar(10)|YES|||",
+			},
+		},
+		{
+			name: "postgres alter column",
+			ddl: `ALTER TABLE users ALTER COLUMN name SET NOT NULL,
+				ALTER COLUMN name SET DEFAULT 'x',
+				ALTER COLUMN page_id TYPE bigint;`,
+			table: "users",
+			columns: []string{
+				"id|int|NO|PRI||auto_increment",
+				"name|varchar(10)|NO||x|",
+				"page_id|bigint|YES|||",
+			},
+			foreignKeys: []*ForeignKeyDescribe{
+				{Name: "fk_page", Columns: []string{"page_id"}, ReferencedTable: "pages", ReferencedColumns: []string{"id"}},
+			},
+		},
+		{
+			name:  "rename column",
+			ddl:   `ALTER TABLE users RENAME COLUMN name TO full_name;`,
+			table: "users",
+			columns: []string{
+				"id|int|NO|PRI||auto_increment",
+				"full_name|varchar(10)|YES|||",
+				"page_id|int|YES|||",
+			},
+			foreignKeys: []*ForeignKeyDescribe{
+				{Name: "fk_page", Columns: []string{"page_id"}, ReferencedTable: "pages", ReferencedColumns: []string{"id"}},
+			},
+		},
+		{
+			name:  "rename referenced table",
+			ddl:   `RENAME TABLE pages TO web_pages;`,
+			table: "users",
+			columns: []string{
+				"id|int|NO|PRI||auto_increment",
+				"name|varchar(10)|YES|||",
+				"page_id|int|YES|||",
+			},
+			foreignKeys: []*ForeignKeyDescribe{
+				{Name: "fk_page", Columns: []string{"page_id"}, ReferencedTable: "web_pages", ReferencedColumns: []string{"id"}},
+			},
+			missing: []string{"pages"},
+		},
+		{
+			name: "create and drop indexes",
+			ddl: `CREATE UNIQUE INDEX uq_name ON users (name);
+				CREATE INDEX idx_page ON users (page_id);
+				CREATE INDEX idx_partial ON users (name) WHERE name IS NOT NULL;
+				DROP INDEX idx_page ON users;`,
+			table: "users",
+			columns: []string{
+				"id|int|NO|PRI||auto_increment",
+				"name|varchar(10)|YES|UNI||",
+				"page_id|int|YES|MUL||",
+			},
+			indexes: []*IndexDescribe{{Name: "uq_name", Unique: true, Columns: []string{"name"}}},
+			foreignKeys: []*ForeignKeyDescribe{
+				{Name: "fk_page", Columns: []string{"page_id"}, ReferencedTable: "pages", ReferencedColumns: []string{"id"}},
+			},
+		},
+		{
+			name:  "drop primary key and foreign key",
+			ddl:   `ALTER TABLE users DROP PRIMARY KEY, DROP FOREIGN KEY fk_page;`,
+			table: "users",
+			columns: []string{
+				"id|int|NO|||auto_increment",
+				"name|varchar(10)|YES|||",
+				"page_id|int|YES|||",
+			},
+		},
+		{
+			name:  "drop table",
+			ddl:   `DROP TABLE IF EXISTS pages;`,
+			table: "users",
+			columns: []string{
+				"id|int|NO|PRI||auto_increment",
+				"name|varchar(10)|YES|||",
+				"page_id|int|YES|||",
+			},
+			foreignKeys: []*ForeignKeyDescribe{
+				{Name: "fk_page", Columns: []string{"page_id"}, ReferencedTable: "pages", ReferencedColumns: []string{"id"}},
+			},
+			missing: []string{"pages"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			src := NewDDLSource(DialectMySQL)
+			if err := src.Exec(schema); err != nil {
+				t.Fatalf("Exec(schema) error = %v", err)
+			}
+			if err := src.Exec(tt.ddl); err != nil {
+				t.Fatalf("Exec() error = %v", err)
+			}
+			tableDescribe, err := src.DescribeTable(tt.table)
+			if err != nil {
+				t.Fatalf("DescribeTable() error = %v", err)
+			}
+			if got := describeDDLColumns(tableDescribe); !reflect.DeepEqual(got, tt.columns) {
+				t.Errorf("columns = %q, want %q", got, tt.columns)
+			}
+			if !reflect.DeepEqual(tableDescribe.Indexes, tt.indexes) {
+				t.Errorf("indexes = %+v, want %+v", tableDescribe.Indexes, tt.indexes)
+			}
+			if !reflect.DeepEqual(tableDescribe.ForeignKeys, tt.foreignKeys) {
+				t.Errorf("foreign keys = %+v, want %+v", tableDescribe.ForeignKeys, tt.foreignKeys)
+			}
+			for _, table := range tt.missing {
+				if _, err := src.DescribeTable(table); err == nil {
+					t.Errorf("DescribeTable(%q) found a dropped table", table)
+				}
+			}
+		})
+	}
+}
+
+func TestDDLSourceAlterErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		ddl  string
+	}{
+		{name: "alter unknown table", ddl: `ALTER TABLE users ADD name text;`},
+		{name: "modify unknown column", ddl: `CREATE TABLE users (id int); ALTER TABLE users MODIFY name text;`},
+		{name: "add after unknown column", ddl: `CREATE TABLE users (id int); ALTER TABLE users ADD name text AFTER email;`},
+		{name: "index on unknown table", ddl: `CREATE INDEX idx_name ON users (name);`},
+		{name: "rename unknown table", ddl: `RENAME TABLE users TO people;`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := NewDDLSource(DialectMySQL).Exec(tt.ddl); err == nil {
+				t.Errorf("Exec(%q) succeeded, want an error", tt.ddl)
+			}
+		})
+	}
+}
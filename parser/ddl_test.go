@@ -0,0 +1,197 @@
+package parser
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// describeDDLColumns formats the columns as "field|type|null|key|default|extra".
+func describeDDLColumns(tableDescribe *TableDescribe) []string {
+	var columns []string
+	for _, column := range tableDescribe.Columns {
+		columns = append(columns, strings.Join([]string{
+			column.Field.String,
+			column.Type.String,
+			column.Null.String,
+			column.Key.String,
+			column.Default.String,
+			column.Extra.String,
+		}, "|"))
+	}
+	return columns
+}
+
+func TestDDLSourceCreateTable(t *testing.T) {
+	tests := []struct {
+		name        string
+		dialect     string
+		ddl         string
+		table       string
+		columns     []string
+		indexes     []*IndexDescribe
+		foreignKeys []*ForeignKeyDescribe
+	}{
+		{
+			name:    "mysql columns",
+			dialect: DialectMySQL,
+			ddl: "CREATE TABLE IF NOT EXISTS `users` (\n" +
+				"  `id` BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,\n" +
+				"  `email` VARCHAR(255) NOT NULL UNIQUE,\n" +
+				"  `name` VARCHAR(100) DEFAULT 'n/a',\n" +
+				"  `balance` DECIMAL(10,2) NOT NULL DEFAULT 0,\n" +
+				"  `updated_at` TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,\n" +
+				"  PRIMARY KEY (`id`)\n" +
+				") ENGINE=InnoDB;",
+			table: "users",
+			columns: []string{
+				"id|bigint unsigned|NO|PRI||auto_increment",
+				"email|varchar(255)|NO|UNI||",
+				"name|varchar(100)|YES||n/a|",
+				"balance|decimal(10,2)|NO||0|",
+				"updated_at|timestamp|NO||current_timestamp|on update current_timestamp",
+			},
+			indexes: []*IndexDescribe{{Unique: true, Columns: []string{"email"}}},
+		},
+		{
+			name:    "postgres columns",
+			dialect: DialectPostgres,
+			ddl: `CREATE TABLE public.pages (
+				id bigserial PRIMARY KEY,
+				"Title" text NOT NULL,
+				published_at timestamptz,
+				score float8
+			);`,
+			table: "pages",
+			columns: []string{
+				"id|bigint|NO|PRI||auto_increment",
+				"Title|text|NO|||",
+				"published_at|timestamp with time zone|YES|||",
+				"score|double precision|YES|||",
+			},
+		},
+		{
+			name:    "postgres arrays",
+			dialect: DialectPostgres,
+			ddl: `CREATE TABLE posts (
+				tags text[] NOT NULL,
+				scores integer ARRAY,
+				matrix int4[3][3],
+				codes varchar(3)[] DEFAULT '{}'
+			);`,
+			table: "posts",
+			columns: []string{
+				"tags|text[]|NO|||",
+				"scores|integer[]|YES|||",
+				"matrix|integer[]|YES|||",
+				"codes|varchar(3)[]|YES||{}|",
+			},
+		},
+		{
+			name:    "sqlite rowid",
+			dialect: DialectSQLite,
+			ddl:     `CREATE TABLE notes (id INTEGER PRIMARY KEY, body TEXT);`,
+			table:   "notes",
+			columns: []string{
+				"id|integer|NO|PRI||auto_increment",
+				"body|text|YES|||",
+			},
+		},
+		{
+			name:    "table constraints",
+			dialect: DialectMySQL,
+			ddl: `CREATE TABLE pages (id int PRIMARY KEY);
+			CREATE TABLE documents (
+				page_id int NOT NULL,
+				version int NOT NULL,
+				slug varchar(50),
+				PRIMARY KEY (page_id, version),
+				UNIQUE KEY uq_slug (slug, version),
+				KEY idx_version (version),
+				CONSTRAINT fk_page FOREIGN KEY (page_id) REFERENCES pages (id)
+			);`,
+			table: "documents",
+			columns: []string{
+				"page_id|int|NO|PRI||",
+				"version|int|NO|PRI||",
+				"slug|varchar(50)|YES|MUL||",
+			},
+			indexes: []*IndexDescribe{
+				{Name: "uq_slug", Unique: true, Columns: []string{"slug", "version"}},
+				{Name: "idx_version", Columns: []string{"version"}},
+			},
+			foreignKeys: []*ForeignKeyDescribe{
+				{Name: "fk_page", Columns: []string{"page_id"}, ReferencedTable: "pages", ReferencedColumns: []string{"id"}},
+			},
+		},
+		{
+			name:    "inline references",
+			dialect: DialectPostgres,
+			ddl: `CREATE TABLE pages (id serial PRIMARY KEY);
+			CREATE TABLE audit_logs (page_id integer REFERENCES pages ON DELETE CASCADE, action text NOT NULL);`,
+			table: "audit_logs",
+			columns: []string{
+				"page_id|integer|YES|||",
+				"action|text|NO|||",
+			},
+			foreignKeys: []*ForeignKeyDescribe{
+				{Columns: []string{"page_id"}, ReferencedTable: "pages"},
+			},
+		},
+		{
+			name:    "create table like",
+			dialect: DialectMySQL,
+			ddl: `CREATE TABLE users (id int PRIMARY KEY, email varchar(10) UNIQUE);
+			CREATE TABLE users_copy LIKE users;`,
+			table: "users_copy",
+			columns: []string{
+				"id|int|NO|PRI||",
+				"email|varchar(10)|YES|UNI||",
+			},
+			indexes: []*IndexDescribe{{Unique: true, Columns: []string{"email"}}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			src := NewDDLSource(tt.dialect)
+			if err := src.Exec(tt.ddl); err != nil {
+				t.Fatalf("Exec() error = %v", err)
+			}
+			tableDescribe, err := src.DescribeTable(tt.table)
+			if err != nil {
+				t.Fatalf("DescribeTable() error = %v", err)
+			}
+			if got := describeDDLColumns(tableDescribe); !reflect.DeepEqual(got, tt.columns) {
+				t.Errorf("columns = %q, want %q", got, tt.columns)
+			}
+			if !reflect.DeepEqual(tableDescribe.Indexes, tt.indexes) {
+				t.Errorf("indexes = %+v, want %+v", tableDescribe.Indexes, tt.indexes)
+			}
+			if !reflect.DeepEqual(tableDescribe.ForeignKeys, tt.foreignKeys) {
+				t.Errorf("foreign keys = %+v, want %+v", tableDescribe.ForeignKeys, tt.foreignKeys)
+			}
+		})
+	}
+}
+
+func TestDDLSourceExecErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		ddl  string
+	}{
+		{name: "missing table name", ddl: `CREATE TABLE (id int);`},
+		{name: "unclosed definitions", ddl: `CREATE TABLE users (id int`},
+		{name: "invalid column", ddl: `CREATE TABLE users (id);`},
+		{name: "primary key on unknown column", ddl: `CREATE TABLE users (id int, PRIMARY KEY (user_id));`},
+		{name: "like unknown table", ddl: `CREATE TABLE users LIKE people;`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := NewDDLSource(DialectMySQL).Exec(tt.ddl); err == nil {
+				t.Errorf("Exec(%q) succeeded, want an error", tt.ddl)
+			}
+		})
+	}
+}
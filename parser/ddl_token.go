@@ -0,0 +1,210 @@
+package parser
+
+import (
+	"fmt"
+	"strings"
+)
+
+type ddlTokenKind int
+
+const (
+	ddlWord ddlTokenKind = iota
+	ddlIdentifier
+	ddlString
+	ddlPunct
+)
+
+type ddlToken struct {
+	kind ddlTokenKind
+	text string
+}
+
+// is reports whether the token is the given unquoted keyword or punctuation.
+func (t ddlToken) is(s string) bool {
+	return (t.kind == ddlWord || t.kind == ddlPunct) && strings.EqualFold(t.text, s)
+}
+
+// tokenizeDDL splits SQL DDL into tokens, dropping whitespaces and comments.
+func tokenizeDDL(ddl string) ([]ddlToken, error) {
+	var tokens []ddlToken
+	for i := 0; i < len(ddl); {
+		c := ddl[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case c == '#' || strings.HasPrefix(ddl[i:], "--"):
+			end := strings.IndexByte(ddl[i:], '\n')
+			if end == -1 {
+				return tokens, nil
+			}
+			i += end + 1
+		case strings.HasPrefix(ddl[i:], "/*"):
+			end := strings.Index(ddl[i+2:], "*/")
+			if end == -1 {
+				return nil, fmt.Errorf("unterminated comment")
+			}
+			i += end + 4
+		case c == '`' || c == '"':
+			text, n, err := readQuoted(ddl[i:], c)
+			if err != nil {
+				return nil, err
+			}
+			tokens = append(tokens, ddlToken{ddlIdentifier, text})
+			i += n
+		case c == '\'':
+			text, n, err := readQuoted(ddl[i:], c)
+			if err != nil {
+				return nil, err
+			}
+			tokens = append(tokens, ddlToken{ddlString, text})
+			i += n
+		case isDDLWordChar(c):
+			start := i
+			for i < len(ddl) && isDDLWordChar(ddl[i]) {
+				i++
+			}
+			tokens = append(tokens, ddlToken{ddlWord, ddl[start:i]})
+		default:
+			tokens = append(tokens, ddlToken{ddlPunct, string(c)})
+			i++
+		}
+	}
+
+	return tokens, nil
+}
+
+// readQuoted reads a quoted text, which escapes its quote by doubling it or by a
+// backslash, returning the unquoted text and the number of bytes read.
+func readQuoted(s string, quote byte) (string, int, error) {
+	var text strings.Builder
+	for i := 1; i < len(s); i++ {
+		switch {
+		case s[i] == '\\' && quote == '\'' && i+1 < len(s):
+			i++
+			text.WriteByte(s[i])
+		case s[i] == quote && i+1 < len(s) && s[i+1] == quote:
+			i++
+			text.WriteByte(quote)
+		case s[i] == quote:
+			return text.String(), i + 1, nil
+		default:
+			text.WriteByte(s[i])
+		}
+	}
+
+	return "", 0, fmt.Errorf("unterminated quoted text %s", s[:1])
+}
+
+func isDDLWordChar(c byte) bool {
+	return c == '_' || c == '$' ||
+		(c >= 'a' && c <= 'z') ||
+		(c >= 'A' && c <= 'Z') ||
+		(c >= '0' && c <= '9')
+}
+
+// splitDDLStatements splits tokens into statements separated by semicolons.
+func splitDDLStatements(tokens []ddlToken) [][]ddlToken {
+	var (
+		statements [][]ddlToken
+		statement  []ddlToken
+	)
+	for _, token := range tokens {
+		if token.is(";") {
+			if len(statement) > 0 {
+				statements = append(statements, statement)
+			}
+			statement = nil
+			continue
+		}
+		statement = append(statement, token)
+	}
+	if len(statement) > 0 {
+		statements = append(statements, statement)
+	}
+
+	return statements
+}
+
+// splitDDLList splits the tokens enclosed by the parentheses starting at
+// tokens[start] by their top level commas. It returns the index after the
+// closing parenthesis.
+func splitDDLList(tokens []ddlToken, start int) ([][]ddlToken, int, error) {
+	if start >= len(tokens) || !tokens[start].is("(") {
+		return nil, 0, fmt.Errorf("expected '('")
+	}
+
+	var (
+		items [][]ddlToken
+		item  []ddlToken
+		depth int
+	)
+	for i := start + 1; i < len(tokens); i++ {
+		token := tokens[i]
+		switch {
+		case token.is("("):
+			depth++
+		case token.is(")") && depth == 0:
+			if len(item) > 0 {
+				items = append(items, item)
+			}
+			return items, i + 1, nil
+		case token.is(")"):
+			depth--
+		case token.is(",") && depth == 0:
+			items = append(items, item)
+			item = nil
+			continue
+		}
+		item = append(item, token)
+	}
+
+	return nil, 0, fmt.Errorf("expected ')'")
+}
+
+// splitDDLTopLevel splits tokens by their commas outside of parentheses.
+func splitDDLTopLevel(tokens []ddlToken) [][]ddlToken {
+	var (
+		items [][]ddlToken
+		item  []ddlToken
+		depth int
+	)
+	for _, token := range tokens {
+		switch {
+		case token.is("("):
+			depth++
+		case token.is(")"):
+			depth--
+		case token.is(",") && depth == 0:
+			items = append(items, item)
+			item = nil
+			continue
+		}
+		item = append(item, token)
+	}
+	if len(item) > 0 {
+		items = append(items, item)
+	}
+
+	return items
+}
+
+// ddlText joins tokens back into SQL text, lower casing the unquoted words.
+func ddlText(tokens []ddlToken) string {
+	var text strings.Builder
+	for i, token := range tokens {
+		if i > 0 && token.kind != ddlPunct &&
+			(tokens[i-1].kind != ddlPunct || tokens[i-1].is(")")) {
+			text.WriteByte(' ')
+		}
+		switch token.kind {
+		case ddlWord:
+			text.WriteString(strings.ToLower(token.text))
+		case ddlString:
+			text.WriteString("'" + strings.ReplaceAll(token.text, "'", "''") + "'")
+		default:
+			text.WriteString(token.text)
+		}
+	}
+
+	return text.String()
+}
@@ -0,0 +1,106 @@
+package parser
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestTokenizeDDL(t *testing.T) {
+	tests := []struct {
+		name    string
+		ddl     string
+		want    []ddlToken
+		wantErr bool
+	}{
+		{
+			name: "words and punctuation",
+			ddl:  "CREATE TABLE users (id int);",
+			want: []ddlToken{
+				{ddlWord, "CREATE"}, {ddlWord, "TABLE"}, {ddlWord, "users"},
+				{ddlPunct, "("}, {ddlWord, "id"}, {ddlWord, "int"}, {ddlPunct, ")"}, {ddlPunct, ";"},
+			},
+		},
+		{
+			name: "comments",
+			ddl:  "-- line\n# hash\n/* block\n */ drop -- trailing",
+			want: []ddlToken{{ddlWord, "drop"}},
+		},
+		{
+			name: "quoted identifiers",
+			ddl:  "`order` \"Page\"\"s\" `a``b`",
+			want: []ddlToken{{ddlIdentifier, "order"}, {ddlIdentifier, `Page"s`}, {ddlIdentifier, "a`b"}},
+		},
+		{
+			name: "strings",
+			ddl:  `'it''s' 'a\'b' ''`,
+			want: []ddlToken{{ddlString, "it's"}, {ddlString, "a'b"}, {ddlString, ""}},
+		},
+		{
+			name:    "unterminated comment",
+			ddl:     "drop /* table",
+			wantErr: true,
+		},
+		{
+			name:    "unterminated identifier",
+			ddl:     "create table `users",
+			wantErr: true,
+		},
+		{
+			name:    "unterminated string",
+			ddl:     "default 'a",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tokenizeDDL(tt.ddl)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("tokenizeDDL() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("tokenizeDDL() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSplitDDLStatements(t *testing.T) {
+	tests := []struct {
+		name string
+		ddl  string
+		want []string
+	}{
+		{
+			name: "statements",
+			ddl:  "drop table a; drop table b",
+			want: []string{"drop table a", "drop table b"},
+		},
+		{
+			name: "empty statements",
+			ddl:  ";; drop table a;;",
+			want: []string{"drop table a"},
+		},
+		{
+			name: "semicolon in string",
+			ddl:  "create table a (b text default ';'); drop table a;",
+			want: []string{"create table a(b text default ';')", "drop table a"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tokens, err := tokenizeDDL(tt.ddl)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got []string
+			for _, statement := range splitDDLStatements(tokens) {
+				got = append(got, ddlText(statement))
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("splitDDLStatements() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,41 @@
+package parser
+
+import (
+	"fmt"
+	"html/template"
+	"strconv"
+	"strings"
+)
+
+const (
+	DialectMySQL    = "mysql"
+	DialectPostgres = "postgres"
+	DialectSQLite   = "sqlite3"
+)
+
+// DialectFromDriver resolves the SQL dialect from a database/sql driver name.
+func DialectFromDriver(driver string) (string, error) {
+	switch driver {
+	case "mysql":
+		return DialectMySQL, nil
+	case "postgres", "pgx":
+		return DialectPostgres, nil
+	case "sqlite3":
+		return DialectSQLite, nil
+	default:
+		return "", fmt.Errorf("unsupported driver '%s'", driver)
+	}
+}
+
+// quoteIdentifier quotes an identifier for the given dialect, doubling the
+// quotes it contains, and escapes it so that it can be written inside a Go
+// interpreted string literal of the generated code.
+func quoteIdentifier(dialect, identifier string) template.HTML {
+	quote := `"`
+	if dialect == DialectMySQL {
+		quote = "`"
+	}
+
+	quoted := strconv.Quote(quote + strings.ReplaceAll(identifier, quote, quote+quote) + quote)
+	return template.HTML(quoted[1 : len(quoted)-1])
+}
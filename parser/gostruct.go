@@ -6,13 +6,13 @@ import (
 )
 
 type GoResolver struct {
-	t *tableDescribe
+	t       *TableDescribe
+	dialect string
 }
 
 type GoStruct struct {
 	Name             string
-	IdName           string
-	IdType           string
+	PrimaryKeys      []*GoField
 	Fields           []*GoField
 	ImportedPackages []string
 }
@@ -36,8 +36,7 @@ func (goResolver *GoResolver) ResolveStruct() (*GoStruct, error) {
 			return nil, err
 		}
 		if isId {
-			goStruct.IdName = goField.Name
-			goStruct.IdType = goField.Type
+			goStruct.PrimaryKeys = append(goStruct.PrimaryKeys, goField)
 		}
 		goStruct.Fields = append(goStruct.Fields, goField)
 	}
@@ -47,7 +46,7 @@ func (goResolver *GoResolver) ResolveStruct() (*GoStruct, error) {
 	return goStruct, nil
 }
 
-func (goResolver *GoResolver) ResolveField(c *columnDescribe) (*GoField, bool, error) {
+func (goResolver *GoResolver) ResolveField(c *ColumnDescribe) (*GoField, bool, error) {
 	nullable := strings.ToLower(c.Null.String) != "no"
 	goType := goResolver.ResolveType(c.Type.String, nullable)
 	goNullType, goNullTypeSel := goResolver.resolveNullType(c.Type.String)
@@ -71,7 +70,14 @@ func (goResolver *GoResolver) ResolveField(c *columnDescribe) (*GoField, bool, e
 }
 
 func (goResolver *GoResolver) ResolveType(s string, nullable bool) string {
+	if element, ok := arrayElementType(s); ok {
+		return goResolver.resolveArrayType(element)
+	}
+
 	s = sanitizeTableType(s)
+	if goResolver.dialect == DialectSQLite {
+		s = sqliteAffinityType(s)
+	}
 	if nullable {
 		nullType, _ := goResolver.resolveNullType(s)
 		return nullType
@@ -80,17 +86,25 @@ func (goResolver *GoResolver) ResolveType(s string, nullable bool) string {
 	switch sanitizeTableType(s) {
 	case "bigint":
 		return "int64"
-	case "int":
+	case "int", "integer":
 		return "int32"
-	case "text", "varchar", "enum", "char", "longtext", "mediumblob":
+	case "smallint":
+		return "int16"
+	case "text", "varchar", "enum", "char", "longtext", "mediumblob",
+		"character varying", "character", "uuid", "json", "jsonb", "user-defined":
 		return "string"
-	case "float":
+	case "float", "double", "real", "double precision":
 		return "float64"
 	case "tinyint":
 		return "int8"
-	case "datetime", "date", "timestamp":
+	case "boolean":
+		return "bool"
+	case "bytea", "blob":
+		return "[]byte"
+	case "datetime", "date", "timestamp",
+		"timestamp without time zone", "timestamp with time zone":
 		return "time.Time"
-	case "decimal":
+	case "decimal", "numeric":
 		return "decimal.Decimal"
 	default:
 		return "unknown"
@@ -98,28 +112,150 @@ func (goResolver *GoResolver) ResolveType(s string, nullable bool) string {
 }
 
 func (goResolver *GoResolver) resolveNullType(s string) (string, string) {
+	if element, ok := arrayElementType(s); ok {
+		// the arrays scan NULL as a nil slice
+		return goResolver.resolveArrayType(element), ""
+	}
+	if goResolver.dialect == DialectSQLite {
+		s = sqliteAffinityType(sanitizeTableType(s))
+	}
+
 	switch sanitizeTableType(strings.ToLower(s)) {
-	case "bigint", "int", "tinyint":
+	case "bigint", "int", "integer", "smallint", "tinyint":
 		return "null.Int", "Int64"
-	case "text", "varchar", "enum", "char", "longtext", "mediumblob":
+	case "text", "varchar", "enum", "char", "longtext", "mediumblob",
+		"character varying", "character", "uuid", "json", "jsonb", "user-defined":
 		return "null.String", "String"
-	case "float":
+	case "float", "double", "real", "double precision":
 		return "null.Float", "Float64"
-	case "datetime", "date", "timestamp":
+	case "boolean":
+		return "null.Bool", "Bool"
+	case "bytea", "blob":
+		return "[]byte", ""
+	case "datetime", "date", "timestamp",
+		"timestamp without time zone", "timestamp with time zone":
 		return "null.Time", "Time"
-	case "decimal":
+	case "decimal", "numeric":
 		return "decimal.NullDecimal", "NullDecimal"
 	default:
 		return "unknown", ""
 	}
 }
 
+// arrayElementType returns the element type of an array column type, e.g.
+// text of text[].
+func arrayElementType(s string) (string, bool) {
+	s = strings.ToLower(strings.TrimSpace(s))
+	if !strings.HasSuffix(s, "[]") {
+		return "", false
+	}
+
+	element := strings.TrimSpace(strings.TrimSuffix(s, "[]"))
+	if alias, ok := ddlTypeAliases[element]; ok {
+		element = alias
+	}
+	return element, true
+}
+
+// resolveArrayType maps an array of the element type to the lib/pq array type
+// holding its values.
+func (goResolver *GoResolver) resolveArrayType(element string) string {
+	switch goResolver.ResolveType(element, false) {
+	case "int64", "int32", "int16", "int8":
+		return "pq.Int64Array"
+	case "float64":
+		return "pq.Float64Array"
+	case "bool":
+		return "pq.BoolArray"
+	case "string":
+		return "pq.StringArray"
+	case "[]byte":
+		return "pq.ByteaArray"
+	default:
+		return "unknown"
+	}
+}
+
+// resolveBaseType returns the type of the values held by the given Go type, i.e.
+// the type wrapped by a null type.
+func resolveBaseType(goType string) string {
+	switch goType {
+	case "null.Int":
+		return "int64"
+	case "null.String":
+		return "string"
+	case "null.Float":
+		return "float64"
+	case "null.Bool":
+		return "bool"
+	case "null.Time":
+		return "time.Time"
+	case "decimal.NullDecimal":
+		return "decimal.Decimal"
+	default:
+		return goType
+	}
+}
+
+// resolveSumType returns the type of the sum of a numeric column, the integers
+// are summed as int64 so that the sum of small integers does not overflow.
+func resolveSumType(baseType string) string {
+	switch baseType {
+	case "int8", "int16", "int32", "int64":
+		return "int64"
+	default:
+		return baseType
+	}
+}
+
+func isNumericType(goType string) bool {
+	switch goType {
+	case "int8", "int16", "int32", "int64", "float64", "decimal.Decimal":
+		return true
+	default:
+		return false
+	}
+}
+
+// sqliteAffinityType maps a declared SQLite column type to the type that
+// represents its affinity, following https://www.sqlite.org/datatype3.html.
+// Boolean and date declarations are kept since the sqlite3 driver scans them
+// into bool and time.Time.
+func sqliteAffinityType(s string) string {
+	switch {
+	case strings.Contains(s, "int"):
+		return "bigint"
+	case strings.Contains(s, "char"),
+		strings.Contains(s, "clob"),
+		strings.Contains(s, "text"):
+		return "text"
+	case strings.Contains(s, "blob"), s == "":
+		return "blob"
+	case strings.Contains(s, "real"),
+		strings.Contains(s, "floa"),
+		strings.Contains(s, "doub"):
+		return "float"
+	case strings.HasPrefix(s, "bool"):
+		return "boolean"
+	case strings.Contains(s, "date"),
+		strings.Contains(s, "time"):
+		return "datetime"
+	case strings.Contains(s, "decimal"):
+		return "decimal"
+	default:
+		return "float"
+	}
+}
+
 func sanitizeTableType(s string) string {
+	s = strings.ToLower(s)
 	bracketIndex := strings.Index(s, "(")
 	if bracketIndex > -1 {
 		return s[:bracketIndex]
 	}
 
+	s = strings.TrimSuffix(s, " zerofill")
+	s = strings.TrimSuffix(s, " unsigned")
 	return s
 }
 
@@ -134,7 +270,9 @@ func snakeToCamel(s string) string {
 func resolveImportedPkg(goFields []*GoField) []string {
 	importedMap := make(map[string]struct{})
 	for _, field := range goFields {
-		if strings.Contains(strings.ToLower(string(field.Type)), "decimal") {
+		if strings.HasPrefix(field.Type, "pq.") {
+			importedMap["github.com/lib/pq"] = struct{}{}
+		} else if strings.Contains(strings.ToLower(string(field.Type)), "decimal") {
 			importedMap["github.com/shopspring/decimal"] = struct{}{}
 		} else if strings.Contains(strings.ToLower(string(field.Type)), "null") {
 			importedMap["github.com/guregu/null"] = struct{}{}
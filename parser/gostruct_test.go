@@ -0,0 +1,50 @@
+package parser
+
+import "testing"
+
+func TestGoResolverResolveType(t *testing.T) {
+	tests := []struct {
+		dialect  string
+		typ      string
+		nullable bool
+		want     string
+	}{
+		{DialectMySQL, "bigint unsigned", false, "int64"},
+		{DialectMySQL, "varchar(255)", true, "null.String"},
+		{DialectMySQL, "decimal(10,2)", true, "decimal.NullDecimal"},
+		{DialectPostgres, "timestamp with time zone", false, "time.Time"},
+		{DialectSQLite, "INTEGER", false, "int64"},
+		{DialectPostgres, "text[]", false, "pq.StringArray"},
+		{DialectPostgres, "text[]", true, "pq.StringArray"},
+		{DialectPostgres, "varchar(3)[]", false, "pq.StringArray"},
+		{DialectPostgres, "bpchar[]", false, "pq.StringArray"},
+		{DialectPostgres, "int4[]", false, "pq.Int64Array"},
+		{DialectPostgres, "bigint[]", true, "pq.Int64Array"},
+		{DialectPostgres, "float8[]", false, "pq.Float64Array"},
+		{DialectPostgres, "bool[]", false, "pq.BoolArray"},
+		{DialectPostgres, "bytea[]", false, "pq.ByteaArray"},
+		{DialectPostgres, "timestamptz[]", false, "unknown"},
+		{DialectPostgres, "point", false, "unknown"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.dialect+" "+tt.typ, func(t *testing.T) {
+			goResolver := GoResolver{dialect: tt.dialect}
+			if got := goResolver.ResolveType(tt.typ, tt.nullable); got != tt.want {
+				t.Errorf("ResolveType(%q, %v) = %q, want %q", tt.typ, tt.nullable, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGoResolverResolveFieldUnknownType(t *testing.T) {
+	goResolver := GoResolver{dialect: DialectPostgres}
+	column := &ColumnDescribe{
+		Field: nullString("ranges"),
+		Type:  nullString("timestamptz[]"),
+		Null:  nullString("NO"),
+	}
+	if _, _, err := goResolver.ResolveField(column); err == nil {
+		t.Error("ResolveField() succeeded on an unsupported array type")
+	}
+}
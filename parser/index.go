@@ -0,0 +1,127 @@
+package parser
+
+import (
+	"strings"
+)
+
+// Index is a secondary index of an object, its finder methods are named after
+// Name, e.g. GetUsersByEmail for a unique index or ListOrdersByUserId for a
+// non-unique one.
+type Index struct {
+	Name   string
+	Unique bool
+	Fields []*Field
+}
+
+// reservedNames are the Go keywords and the names used by the generated
+// methods, which could not be used as parameter names.
+var reservedNames = map[string]bool{
+	"break": true, "case": true, "chan": true, "const": true, "continue": true,
+	"default": true, "defer": true, "else": true, "fallthrough": true, "for": true,
+	"func": true, "go": true, "goto": true, "if": true, "import": true,
+	"interface": true, "map": true, "package": true, "range": true, "return": true,
+	"select": true, "struct": true, "switch": true, "type": true, "var": true,
+	"ctx": true, "repo": true, "filter": true,
+}
+
+// reservedIndexNames are the index names whose finders, Get{{Name}}By{{Index}},
+// would clash with the Get{{Name}}ByKey finder of a composite primary key.
+var reservedIndexNames = map[string]bool{
+	"Key": true,
+}
+
+// resolveIndexes resolves the indexes of the object, skipping the ones on the
+// primary key and the ones covering the same columns as a previous index. A
+// unique index is preferred over a non-unique one on the same columns.
+func resolveIndexes(obj *Object, indexDescribes []*IndexDescribe) []*Index {
+	var primaryKeys []string
+	for _, primaryKey := range obj.PrimaryKeys {
+		primaryKeys = append(primaryKeys, strings.ToLower(string(primaryKey.DBField)))
+	}
+	primaryKeyColumns := strings.Join(primaryKeys, ",")
+
+	var indexes []*Index
+	indexesByColumns := make(map[string]*Index)
+	for _, indexDescribe := range indexDescribes {
+		index := &Index{Unique: indexDescribe.Unique}
+		var (
+			names   []string
+			columns []string
+		)
+		for _, column := range indexDescribe.Columns {
+			field := obj.field(column)
+			if field == nil {
+				index = nil
+				break
+			}
+			index.Fields = append(index.Fields, field)
+			names = append(names, string(field.GoName))
+			columns = append(columns, strings.ToLower(column))
+		}
+		if index == nil || len(index.Fields) == 0 {
+			continue
+		}
+
+		key := strings.Join(columns, ",")
+		if key == primaryKeyColumns {
+			continue
+		}
+		if existing, ok := indexesByColumns[key]; ok {
+			existing.Unique = existing.Unique || index.Unique
+			continue
+		}
+
+		index.Name = strings.Join(names, "And")
+		if obj.CompositeKey && reservedIndexNames[index.Name] {
+			index.Name += "Index"
+		}
+		indexesByColumns[key] = index
+		indexes = append(indexes, index)
+	}
+
+	return indexes
+}
+
+func privateName(name string) string {
+	if name == "" {
+		return name
+	}
+
+	private := strings.ToLower(name[:1]) + name[1:]
+	if reservedNames[private] {
+		return private + "Value"
+	}
+	return private
+}
+
+// resolveConflictFields resolves the columns on which an upsert conflicts, the
+// primary key unless it is generated by the database, or the first unique
+// index otherwise.
+func resolveConflictFields(obj *Object) []*Field {
+	conflictFields := obj.PrimaryKeys
+	for _, primaryKey := range obj.PrimaryKeys {
+		if primaryKey.AutoIncrement {
+			conflictFields = nil
+			break
+		}
+	}
+	if len(conflictFields) > 0 {
+		return conflictFields
+	}
+
+	for _, index := range obj.Indexes {
+		if index.Unique {
+			return index.Fields
+		}
+	}
+	return nil
+}
+
+func containsField(fields []*Field, field *Field) bool {
+	for _, f := range fields {
+		if f == field {
+			return true
+		}
+	}
+	return false
+}
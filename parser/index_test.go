@@ -0,0 +1,187 @@
+package parser
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func parseDDLTable(t *testing.T, ddl, table string) *Object {
+	t.Helper()
+	source := NewDDLSource(DialectMySQL)
+	if err := source.Exec(ddl); err != nil {
+		t.Fatal(err)
+	}
+	obj, err := NewTableParser(source).Parse(table)
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	return obj
+}
+
+// describeIndexes formats the indexes as "name|unique|field,field".
+func describeIndexes(indexes []*Index) []string {
+	var described []string
+	for _, index := range indexes {
+		unique := "non-unique"
+		if index.Unique {
+			unique = "unique"
+		}
+		described = append(described, index.Name+"|"+unique+"|"+fieldNames(index.Fields))
+	}
+	return described
+}
+
+func fieldNames(fields []*Field) string {
+	var names []string
+	for _, field := range fields {
+		names = append(names, string(field.DBField))
+	}
+	return strings.Join(names, ",")
+}
+
+func TestResolveIndexes(t *testing.T) {
+	tests := []struct {
+		name  string
+		ddl   string
+		table string
+		want  []string
+	}{
+		{
+			name: "single and multi column indexes",
+			ddl: `CREATE TABLE orders (
+				id int PRIMARY KEY,
+				user_id int NOT NULL,
+				created_at datetime NOT NULL,
+				code varchar(10) NOT NULL,
+				UNIQUE KEY uq_code (code),
+				KEY idx_user (user_id, created_at)
+			);`,
+			want: []string{"Code|unique|code", "UserIdAndCreatedAt|non-unique|user_id,created_at"},
+		},
+		{
+			name: "primary key indexes are skipped",
+			ddl: `CREATE TABLE orders (
+				id int PRIMARY KEY,
+				code varchar(10) NOT NULL,
+				UNIQUE KEY uq_id (id),
+				KEY idx_code (code)
+			);`,
+			want: []string{"Code|non-unique|code"},
+		},
+		{
+			name: "composite primary key indexes are skipped",
+			ddl: `CREATE TABLE orders (
+				user_id int NOT NULL,
+				version int NOT NULL,
+				PRIMARY KEY (user_id, version),
+				UNIQUE KEY uq_key (user_id, version),
+				KEY idx_version (version)
+			);`,
+			want: []string{"Version|non-unique|version"},
+		},
+		{
+			name: "same columns are deduplicated",
+			ddl: `CREATE TABLE orders (
+				id int PRIMARY KEY,
+				code varchar(10) NOT NULL,
+				KEY idx_code (code),
+				KEY idx_code_again (code)
+			);`,
+			want: []string{"Code|non-unique|code"},
+		},
+		{
+			name: "unique preferred over non-unique",
+			ddl: `CREATE TABLE orders (
+				id int PRIMARY KEY,
+				code varchar(10) NOT NULL,
+				KEY idx_code (code),
+				UNIQUE KEY uq_code (code)
+			);`,
+			want: []string{"Code|unique|code"},
+		},
+		{
+			name: "key column with a composite primary key",
+			ddl: "CREATE TABLE settings (\n" +
+				"  user_id int NOT NULL,\n" +
+				"  version int NOT NULL,\n" +
+				"  `key` varchar(50) NOT NULL,\n" +
+				"  PRIMARY KEY (user_id, version),\n" +
+				"  UNIQUE KEY uq_key (`key`)\n" +
+				");",
+			table: "settings",
+			want:  []string{"KeyIndex|unique|key"},
+		},
+		{
+			name: "key column with a single primary key",
+			ddl: "CREATE TABLE settings (\n" +
+				"  id int PRIMARY KEY,\n" +
+				"  `key` varchar(50) NOT NULL,\n" +
+				"  UNIQUE KEY uq_key (`key`)\n" +
+				");",
+			table: "settings",
+			want:  []string{"Key|unique|key"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			table := tt.table
+			if table == "" {
+				table = "orders"
+			}
+			obj := parseDDLTable(t, tt.ddl, table)
+			if got := describeIndexes(obj.Indexes); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("indexes = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResolveConflictFields(t *testing.T) {
+	tests := []struct {
+		name string
+		ddl  string
+		want string
+	}{
+		{
+			name: "primary key",
+			ddl:  `CREATE TABLE orders (code varchar(10) PRIMARY KEY, email varchar(50) UNIQUE);`,
+			want: "code",
+		},
+		{
+			name: "composite primary key",
+			ddl:  `CREATE TABLE orders (user_id int, version int, email varchar(50) UNIQUE, PRIMARY KEY (user_id, version));`,
+			want: "user_id,version",
+		},
+		{
+			name: "first unique index of an auto increment primary key",
+			ddl: `CREATE TABLE orders (
+				id int AUTO_INCREMENT PRIMARY KEY,
+				user_id int NOT NULL,
+				email varchar(50) NOT NULL,
+				KEY idx_user (user_id),
+				UNIQUE KEY uq_email (email)
+			);`,
+			want: "email",
+		},
+		{
+			name: "no unique index of an auto increment primary key",
+			ddl:  `CREATE TABLE orders (id int AUTO_INCREMENT PRIMARY KEY, user_id int, KEY idx_user (user_id));`,
+		},
+		{
+			name: "no primary key",
+			ddl:  `CREATE TABLE orders (user_id int, email varchar(50) UNIQUE);`,
+			want: "email",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			obj := parseDDLTable(t, tt.ddl, "orders")
+			if got := fieldNames(obj.ConflictFields); got != tt.want {
+				t.Errorf("conflict fields = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
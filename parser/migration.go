@@ -0,0 +1,105 @@
+package parser
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+type migrationFile struct {
+	version uint64
+	name    string
+	path    string
+}
+
+// NewMigrationSource replays the up migrations of a golang-migrate or goose
+// migrations directory into the described tables, in version order.
+func NewMigrationSource(dialect, dir string) (*DDLSource, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var migrations []*migrationFile
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() ||
+			!strings.HasSuffix(name, ".sql") ||
+			strings.HasSuffix(name, ".down.sql") {
+			continue
+		}
+
+		versionEnd := strings.IndexFunc(name, func(r rune) bool {
+			return r < '0' || r > '9'
+		})
+		version, err := strconv.ParseUint(name[:versionEnd], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid migration version of '%s'", name)
+		}
+
+		migrations = append(migrations, &migrationFile{
+			version: version,
+			name:    name,
+			path:    filepath.Join(dir, name),
+		})
+	}
+	if len(migrations) == 0 {
+		return nil, fmt.Errorf("no migrations found on '%s'", dir)
+	}
+
+	sort.Slice(migrations, func(i, j int) bool {
+		if migrations[i].version != migrations[j].version {
+			return migrations[i].version < migrations[j].version
+		}
+		return migrations[i].name < migrations[j].name
+	})
+
+	src := NewDDLSource(dialect)
+	for _, migration := range migrations {
+		content, err := os.ReadFile(migration.path)
+		if err != nil {
+			return nil, err
+		}
+		if err := src.Exec(gooseUpSection(string(content))); err != nil {
+			return nil, fmt.Errorf("%s: %v", migration.name, err)
+		}
+	}
+
+	return src, nil
+}
+
+// gooseUpSection returns the statements between the "-- +goose Up" and
+// "-- +goose Down" annotations, or the whole migration when it is not a goose
+// migration.
+func gooseUpSection(migration string) string {
+	var (
+		up      strings.Builder
+		isGoose bool
+		isUp    bool
+	)
+	for _, line := range strings.SplitAfter(migration, "\n") {
+		annotation := strings.Fields(strings.TrimPrefix(strings.TrimSpace(line), "--"))
+		if len(annotation) >= 2 && annotation[0] == "+goose" {
+			isGoose = true
+			switch strings.ToLower(annotation[1]) {
+			case "up":
+				isUp = true
+			case "down":
+				isUp = false
+			}
+			continue
+		}
+
+		if isUp {
+			up.WriteString(line)
+		}
+	}
+
+	if !isGoose {
+		return migration
+	}
+	return up.String()
+}
@@ -0,0 +1,175 @@
+package parser
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestGooseUpSection(t *testing.T) {
+	tests := []struct {
+		name      string
+		migration string
+		want      string
+	}{
+		{
+			name:      "not a goose migration",
+			migration: "CREATE TABLE users (id int);\n",
+			want:      "CREATE TABLE users (id int);\n",
+		},
+		{
+			name: "up and down sections",
+			migration: "-- +goose Up\n" +
+				"CREATE TABLE users (id int);\n" +
+				"-- +goose Down\n" +
+				"DROP TABLE users;\n",
+			want: "CREATE TABLE users (id int);\n",
+		},
+		{
+			name: "down section first",
+			migration: "-- +goose Down\n" +
+				"DROP TABLE users;\n" +
+				"-- +goose Up\n" +
+				"CREATE TABLE users (id int);\n",
+			want: "CREATE TABLE users (id int);\n",
+		},
+		{
+			name: "statement blocks",
+			migration: "--  +goose UP\n" +
+				"-- +goose StatementBegin\n" +
+				"CREATE TABLE users (id int);\n" +
+				"-- +goose StatementEnd\n" +
+				"-- +goose down\n" +
+				"DROP TABLE users;\n",
+			want: "CREATE TABLE users (id int);\n",
+		},
+		{
+			name:      "without up section",
+			migration: "-- +goose Down\nDROP TABLE users;\n",
+			want:      "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := gooseUpSection(tt.migration); got != tt.want {
+				t.Errorf("gooseUpSection() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewMigrationSource(t *testing.T) {
+	tests := []struct {
+		name    string
+		files   map[string]string
+		tables  map[string][]string
+		wantErr string
+	}{
+		{
+			name: "golang-migrate skips the down migrations",
+			files: map[string]string{
+				"1_create_users.up.sql":   "CREATE TABLE users (id int PRIMARY KEY, name text);",
+				"1_create_users.down.sql": "DROP TABLE users;",
+				"2_add_email.up.sql":      "ALTER TABLE users ADD COLUMN email text NOT NULL;",
+				"2_add_email.down.sql":    "ALTER TABLE users DROP COLUMN email;",
+			},
+			tables: map[string][]string{
+				"users": {"id|int|NO|PRI||", "name|text|YES|||", "email|text|NO|||"},
+			},
+		},
+		{
+			name: "numeric version order",
+			files: map[string]string{
+				"9_create_users.up.sql":  "CREATE TABLE users (id int PRIMARY KEY, name text);",
+				"10_alter_users.up.sql":  "ALTER TABLE users MODIFY name varchar(20) NOT NULL;",
+				"100_drop_name.up.sql":   "ALTER TABLE users DROP COLUMN name;",
+				"0011_add_email.up.sql":  "ALTER TABLE users ADD email text;",
+				"0011_add_active.up.sql": "ALTER TABLE users ADD active boolean;",
+			},
+			tables: map[string][]string{
+				"users": {"id|int|NO|PRI||", "active|boolean|YES|||", "email|text|YES|||"},
+			},
+		},
+		{
+			name: "goose replay across files",
+			files: map[string]string{
+				"20210101000000_create.sql": "-- +goose Up\n" +
+					"CREATE TABLE users (id int PRIMARY KEY, name text);\n" +
+					"CREATE TABLE sessions (id int PRIMARY KEY, user_id int REFERENCES users (id));\n" +
+					"-- +goose Down\n" +
+					"DROP TABLE sessions;\nDROP TABLE users;\n",
+				"20210102000000_rename.sql": "-- +goose Up\n" +
+					"ALTER TABLE users RENAME COLUMN name TO full_name;\n" +
+					"DROP TABLE sessions;\n" +
+					"-- +goose Down\n" +
+					"CREATE TABLE sessions (id int PRIMARY KEY);\n",
+				"README.md": "not a migration",
+			},
+			tables: map[string][]string{
+				"users": {"id|int|NO|PRI||", "full_name|text|YES|||"},
+			},
+		},
+		{
+			name:    "no migrations",
+			files:   map[string]string{"README.md": "not a migration"},
+			wantErr: "no migrations found",
+		},
+		{
+			name:    "invalid version",
+			files:   map[string]string{"create_users.up.sql": "CREATE TABLE users (id int);"},
+			wantErr: "invalid migration version of 'create_users.up.sql'",
+		},
+		{
+			name: "failing migration",
+			files: map[string]string{
+				"1_alter_users.up.sql": "ALTER TABLE users ADD email text;",
+			},
+			wantErr: "1_alter_users.up.sql: table 'users' not found",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			if err := os.Mkdir(filepath.Join(dir, "1_ignored.up.sql"), os.ModePerm); err != nil {
+				t.Fatal(err)
+			}
+			for name, content := range tt.files {
+				if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			src, err := NewMigrationSource(DialectMySQL, dir)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("NewMigrationSource() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("NewMigrationSource() error = %v", err)
+			}
+
+			var tables []string
+			for table := range tt.tables {
+				tables = append(tables, table)
+			}
+			if got, _ := src.ListTables(); !reflect.DeepEqual(got, tables) {
+				t.Errorf("ListTables() = %q, want %q", got, tables)
+			}
+			for table, columns := range tt.tables {
+				tableDescribe, err := src.DescribeTable(table)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if got := describeDDLColumns(tableDescribe); !reflect.DeepEqual(got, columns) {
+					t.Errorf("%s columns = %q, want %q", table, got, columns)
+				}
+			}
+		})
+	}
+}
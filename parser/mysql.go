@@ -0,0 +1,114 @@
+package parser
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type mysqlIndexColumn struct {
+	NonUnique  int            `db:"Non_unique"`
+	KeyName    string         `db:"Key_name"`
+	SeqInIndex int            `db:"Seq_in_index"`
+	ColumnName sql.NullString `db:"Column_name"`
+}
+
+// MySQLSource describes tables from a live MySQL connection.
+type MySQLSource struct {
+	db *sqlx.DB
+}
+
+func NewMySQLSource(db *sqlx.DB) *MySQLSource {
+	return &MySQLSource{
+		db: db,
+	}
+}
+
+func (src *MySQLSource) Dialect() string {
+	return DialectMySQL
+}
+
+func (src *MySQLSource) DescribeTable(table string) (*TableDescribe, error) {
+	columnDescribes := []*ColumnDescribe{}
+	err := src.db.Select(
+		&columnDescribes,
+		fmt.Sprintf("DESCRIBE `%s`", table))
+	if err != nil {
+		return nil, err
+	}
+
+	foreignKeys := []*foreignKeyColumn{}
+	err = src.db.Select(&foreignKeys, `SELECT kcu.CONSTRAINT_NAME AS constraint_name,
+			kcu.COLUMN_NAME AS column_name,
+			kcu.REFERENCED_TABLE_NAME AS referenced_table_name,
+			kcu.REFERENCED_COLUMN_NAME AS referenced_column_name
+		FROM information_schema.KEY_COLUMN_USAGE kcu
+		JOIN information_schema.REFERENTIAL_CONSTRAINTS rc
+			ON rc.CONSTRAINT_SCHEMA = kcu.CONSTRAINT_SCHEMA
+			AND rc.CONSTRAINT_NAME = kcu.CONSTRAINT_NAME
+			AND rc.TABLE_NAME = kcu.TABLE_NAME
+		WHERE kcu.TABLE_SCHEMA = DATABASE() AND kcu.TABLE_NAME = ?
+		ORDER BY kcu.CONSTRAINT_NAME, kcu.ORDINAL_POSITION`, table)
+	if err != nil {
+		return nil, err
+	}
+
+	// SHOW INDEX columns differ between the server versions
+	indexColumns := []*mysqlIndexColumn{}
+	err = src.db.Unsafe().Select(&indexColumns, fmt.Sprintf("SHOW INDEX FROM `%s`", table))
+	if err != nil {
+		return nil, err
+	}
+
+	return &TableDescribe{
+		Name:        table,
+		Columns:     columnDescribes,
+		ForeignKeys: groupForeignKeys(foreignKeys),
+		Indexes:     groupMySQLIndexes(indexColumns),
+	}, nil
+}
+
+func (src *MySQLSource) ListTables() ([]string, error) {
+	tables := []string{}
+	err := src.db.Select(&tables, `SELECT TABLE_NAME FROM information_schema.TABLES
+		WHERE TABLE_SCHEMA = DATABASE() AND TABLE_TYPE = 'BASE TABLE'
+		ORDER BY TABLE_NAME`)
+	if err != nil {
+		return nil, err
+	}
+
+	return tables, nil
+}
+
+// groupMySQLIndexes groups the SHOW INDEX rows into their secondary indexes,
+// skipping the functional ones.
+func groupMySQLIndexes(indexColumns []*mysqlIndexColumn) []*IndexDescribe {
+	var (
+		indexes    []*IndexDescribe
+		functional = make(map[string]bool)
+	)
+	for _, indexColumn := range indexColumns {
+		if !indexColumn.ColumnName.Valid {
+			functional[indexColumn.KeyName] = true
+		}
+	}
+
+	for _, indexColumn := range indexColumns {
+		if indexColumn.KeyName == "PRIMARY" || functional[indexColumn.KeyName] {
+			continue
+		}
+
+		n := len(indexes)
+		if n == 0 || indexes[n-1].Name != indexColumn.KeyName {
+			indexes = append(indexes, &IndexDescribe{
+				Name:   indexColumn.KeyName,
+				Unique: indexColumn.NonUnique == 0,
+			})
+			n++
+		}
+		indexes[n-1].Columns = append(indexes[n-1].Columns, indexColumn.ColumnName.String)
+	}
+
+	return indexes
+}
@@ -5,12 +5,11 @@ import (
 	"fmt"
 	"html/template"
 	"strings"
-
-	"github.com/jmoiron/sqlx"
 )
 
 type ObjectParser struct {
-	db *sqlx.DB
+	source    SchemaSource
+	relations map[string][]*ForeignKeyDescribe
 }
 
 type Object struct {
@@ -18,17 +17,38 @@ type Object struct {
 	IdName                      string
 	IdDBName                    string
 	IdType                      string
+	PrimaryKeys                 []*Field
+	HasPrimaryKey               bool
+	CompositeKey                bool
+	PrimaryKeysQuery            template.HTML
 	Table                       string
+	QuotedTable                 template.HTML
 	PrivateName                 string
 	LowerName                   string
 	ImportedPackages            []string
 	Fields                      []*Field
-	DBFieldsSeperatedCommas     string
+	DBFieldsSeperatedCommas     template.HTML
 	PlaceholdersSeparatedCommas string
+	BelongsTo                   []*Relation
+	HasMany                     []*Relation
+	Relations                   []*Relation
+	Indexes                     []*Index
+	ConflictFields              []*Field
+	UpsertFields                []*Field
+	QueryImportedPackages       []string
+
+	foreignKeys []*ForeignKeyDescribe
 }
 
 type Field struct {
 	AutoIncrement     bool
+	Nullable          bool
+	Numeric           bool
+	Temporal          bool
+	Text              bool
+	PrivateName       template.HTML
+	BaseType          template.HTML
+	SumType           template.HTML
 	ObjectName        template.HTML
 	ObjectPrivateName template.HTML
 	GoName            template.HTML
@@ -37,14 +57,20 @@ type Field struct {
 	GoNullTypeSel     template.HTML
 	GoTag             template.HTML
 	DBField           template.HTML
+	QuotedDBField     template.HTML
 }
 
-type tableDescribe struct {
-	Name    string
-	Columns []*columnDescribe
+// TableDescribe is the description of a table returned by a SchemaSource.
+type TableDescribe struct {
+	Name        string
+	Columns     []*ColumnDescribe
+	ForeignKeys []*ForeignKeyDescribe
+	Indexes     []*IndexDescribe
 }
 
-type columnDescribe struct {
+// ColumnDescribe is the description of a table column, following the shape of
+// the MySQL DESCRIBE statement output.
+type ColumnDescribe struct {
 	Field   sql.NullString `db:"Field"`
 	Type    sql.NullString `db:"Type"`
 	Null    sql.NullString `db:"Null"`
@@ -53,19 +79,42 @@ type columnDescribe struct {
 	Extra   sql.NullString `db:"Extra"`
 }
 
-func NewTableParser(db *sqlx.DB) *ObjectParser {
+// ForeignKeyDescribe is the description of a foreign key constraint of a
+// table. ReferencedColumns is empty when the constraint references the primary
+// key of the referenced table implicitly.
+type ForeignKeyDescribe struct {
+	Name              string
+	Columns           []string
+	ReferencedTable   string
+	ReferencedColumns []string
+}
+
+// IndexDescribe is the description of a secondary index of a table, the primary
+// key is not described as an index.
+type IndexDescribe struct {
+	Name    string
+	Unique  bool
+	Columns []string
+}
+
+func NewTableParser(source SchemaSource) *ObjectParser {
 	return &ObjectParser{
-		db: db,
+		source:    source,
+		relations: make(map[string][]*ForeignKeyDescribe),
 	}
 }
 
+func (tp *ObjectParser) Dialect() string {
+	return tp.source.Dialect()
+}
+
 func (tp *ObjectParser) Parse(table string) (*Object, error) {
 	tableDescribe, err := tp.parseTable(table)
 	if err != nil {
 		return nil, err
 	}
 
-	goResolver := GoResolver{tableDescribe}
+	goResolver := GoResolver{t: tableDescribe, dialect: tp.Dialect()}
 	goStruct, err := goResolver.ResolveStruct()
 	if err != nil {
 		return nil, err
@@ -73,12 +122,13 @@ func (tp *ObjectParser) Parse(table string) (*Object, error) {
 
 	obj := &Object{
 		Name:             goStruct.Name,
-		IdName:           strings.ToLower(goStruct.IdName),
-		IdType:           goStruct.IdType,
+		CompositeKey:     len(goStruct.PrimaryKeys) > 1,
 		Table:            table,
+		QuotedTable:      quoteIdentifier(tp.Dialect(), table),
 		PrivateName:      strings.ToLower(goStruct.Name[0:1]) + goStruct.Name[1:],
 		LowerName:        strings.ToLower(goStruct.Name),
 		ImportedPackages: goStruct.ImportedPackages,
+		foreignKeys:      tableDescribe.ForeignKeys,
 	}
 
 	var (
@@ -87,13 +137,11 @@ func (tp *ObjectParser) Parse(table string) (*Object, error) {
 	)
 	for index, goField := range goStruct.Fields {
 		column := tableDescribe.Columns[index]
-		if column.Key.String == "PRI" {
-			obj.IdDBName = column.Field.String
-		}
-
 		autoIncrement := column.Extra.String == "auto_increment"
-		obj.Fields = append(obj.Fields, &Field{
+		field := &Field{
 			AutoIncrement:     autoIncrement,
+			Nullable:          strings.ToLower(column.Null.String) != "no",
+			PrivateName:       template.HTML(privateName(goField.Name)),
 			ObjectName:        template.HTML(obj.Name),
 			ObjectPrivateName: template.HTML(obj.PrivateName),
 			GoName:            template.HTML(goField.Name),
@@ -102,27 +150,71 @@ func (tp *ObjectParser) Parse(table string) (*Object, error) {
 			GoNullTypeSel:     template.HTML(goField.NullTypeSel),
 			GoTag:             template.HTML(goField.Tag),
 			DBField:           template.HTML(column.Field.String),
-		})
+			QuotedDBField:     quoteIdentifier(tp.Dialect(), column.Field.String),
+		}
+		baseType := resolveBaseType(goField.Type)
+		field.BaseType = template.HTML(baseType)
+		field.SumType = template.HTML(resolveSumType(baseType))
+		field.Numeric = isNumericType(baseType)
+		field.Temporal = baseType == "time.Time"
+		field.Text = baseType == "string"
+		obj.Fields = append(obj.Fields, field)
+		if column.Key.String == "PRI" {
+			obj.PrimaryKeys = append(obj.PrimaryKeys, field)
+		}
 		if !autoIncrement {
-			dbFields = append(dbFields, column.Field.String)
+			dbFields = append(dbFields, string(field.QuotedDBField))
 			placeholders = append(placeholders, "?")
 		}
 	}
-	obj.DBFieldsSeperatedCommas = strings.Join(dbFields, `,
-	`)
+	var primaryKeysQuery []string
+	for _, primaryKey := range obj.PrimaryKeys {
+		primaryKeysQuery = append(primaryKeysQuery, string(primaryKey.QuotedDBField)+" = ?")
+	}
+	obj.PrimaryKeysQuery = template.HTML(strings.Join(primaryKeysQuery, " AND "))
+	obj.HasPrimaryKey = len(obj.PrimaryKeys) > 0
+	if len(obj.PrimaryKeys) == 1 {
+		obj.IdName = strings.ToLower(string(obj.PrimaryKeys[0].GoName))
+		obj.IdType = string(obj.PrimaryKeys[0].GoType)
+		obj.IdDBName = string(obj.PrimaryKeys[0].DBField)
+	}
+	obj.Indexes = resolveIndexes(obj, tableDescribe.Indexes)
+	obj.ConflictFields = resolveConflictFields(obj)
+	for _, field := range obj.Fields {
+		if !field.AutoIncrement && !containsField(obj.ConflictFields, field) {
+			obj.UpsertFields = append(obj.UpsertFields, field)
+		}
+	}
+	// the query files use the base types on the filters, and the field types on
+	// the cursors, only generated with a primary key for the not nullable
+	// fields, and on the index finders
+	var queryFields []*GoField
+	for _, field := range obj.Fields {
+		queryFields = append(queryFields, &GoField{Type: string(field.BaseType)})
+		if obj.HasPrimaryKey && !field.Nullable {
+			queryFields = append(queryFields, &GoField{Type: string(field.GoType)})
+		}
+	}
+	for _, index := range obj.Indexes {
+		for _, field := range index.Fields {
+			queryFields = append(queryFields, &GoField{Type: string(field.GoType)})
+		}
+	}
+	obj.QueryImportedPackages = resolveImportedPkg(queryFields)
+	obj.DBFieldsSeperatedCommas = template.HTML(strings.Join(dbFields, ", "))
 	obj.PlaceholdersSeparatedCommas = strings.Join(placeholders, `,
 	`)
 	return obj, nil
 }
 
-func (tp *ObjectParser) parseTable(table string) (*tableDescribe, error) {
-	columnDescribes := []*columnDescribe{}
-	err := tp.db.Select(
-		&columnDescribes,
-		fmt.Sprintf("DESCRIBE `%s`", table))
+func (tp *ObjectParser) parseTable(table string) (*TableDescribe, error) {
+	tableDescribe, err := tp.source.DescribeTable(table)
 	if err != nil {
 		return nil, err
 	}
+	if len(tableDescribe.Columns) == 0 {
+		return nil, fmt.Errorf("table '%s' not found", table)
+	}
 
-	return &tableDescribe{table, columnDescribes}, nil
+	return tableDescribe, nil
 }
@@ -0,0 +1,128 @@
+package parser
+
+import (
+	"github.com/jmoiron/sqlx"
+)
+
+const postgresDescribeQuery = `SELECT c.column_name AS "Field",
+	CASE WHEN c.data_type = 'ARRAY' THEN ltrim(c.udt_name, '_') || '[]'
+		ELSE c.data_type END AS "Type",
+	c.is_nullable AS "Null",
+	CASE WHEN pk.column_name IS NOT NULL THEN 'PRI' ELSE '' END AS "Key",
+	c.column_default AS "Default",
+	CASE WHEN c.column_default LIKE 'nextval(%' OR c.is_identity = 'YES'
+		THEN 'auto_increment' ELSE '' END AS "Extra"
+FROM information_schema.columns c
+LEFT JOIN (
+	SELECT a.attname AS column_name
+	FROM pg_catalog.pg_index i
+	JOIN pg_catalog.pg_attribute a ON a.attrelid = i.indrelid AND a.attnum = ANY(i.indkey)
+	WHERE i.indrelid = quote_ident($1)::regclass AND i.indisprimary
+) pk ON pk.column_name = c.column_name
+WHERE c.table_schema = current_schema() AND c.table_name = $1
+ORDER BY c.ordinal_position`
+
+const postgresForeignKeysQuery = `SELECT kcu.constraint_name,
+	kcu.column_name,
+	ref.table_name AS referenced_table_name,
+	ref.column_name AS referenced_column_name
+FROM information_schema.referential_constraints rc
+JOIN information_schema.key_column_usage kcu
+	ON kcu.constraint_schema = rc.constraint_schema
+	AND kcu.constraint_name = rc.constraint_name
+JOIN information_schema.key_column_usage ref
+	ON ref.constraint_schema = rc.unique_constraint_schema
+	AND ref.constraint_name = rc.unique_constraint_name
+	AND ref.ordinal_position = kcu.position_in_unique_constraint
+WHERE kcu.table_schema = current_schema() AND kcu.table_name = $1
+ORDER BY kcu.constraint_name, kcu.ordinal_position`
+
+// postgresIndexesQuery describes the key columns of the secondary indexes,
+// skipping the expression and partial ones.
+const postgresIndexesQuery = `SELECT i.relname AS index_name,
+	ix.indisunique AS is_unique,
+	a.attname AS column_name
+FROM pg_catalog.pg_index ix
+JOIN pg_catalog.pg_class i ON i.oid = ix.indexrelid
+JOIN LATERAL unnest(ix.indkey::int2[]) WITH ORDINALITY AS k(attnum, n) ON true
+JOIN pg_catalog.pg_attribute a ON a.attrelid = ix.indrelid AND a.attnum = k.attnum
+WHERE ix.indrelid = quote_ident($1)::regclass
+	AND NOT ix.indisprimary
+	AND ix.indexprs IS NULL
+	AND ix.indpred IS NULL
+	AND k.n <= ix.indnkeyatts
+ORDER BY i.relname, k.n`
+
+type postgresIndexColumn struct {
+	IndexName  string `db:"index_name"`
+	IsUnique   bool   `db:"is_unique"`
+	ColumnName string `db:"column_name"`
+}
+
+// PostgresSource describes tables of the current schema from a live
+// PostgreSQL connection.
+type PostgresSource struct {
+	db *sqlx.DB
+}
+
+func NewPostgresSource(db *sqlx.DB) *PostgresSource {
+	return &PostgresSource{
+		db: db,
+	}
+}
+
+func (src *PostgresSource) Dialect() string {
+	return DialectPostgres
+}
+
+func (src *PostgresSource) DescribeTable(table string) (*TableDescribe, error) {
+	columnDescribes := []*ColumnDescribe{}
+	err := src.db.Select(&columnDescribes, postgresDescribeQuery, table)
+	if err != nil {
+		return nil, err
+	}
+
+	foreignKeys := []*foreignKeyColumn{}
+	err = src.db.Select(&foreignKeys, postgresForeignKeysQuery, table)
+	if err != nil {
+		return nil, err
+	}
+
+	indexColumns := []*postgresIndexColumn{}
+	err = src.db.Select(&indexColumns, postgresIndexesQuery, table)
+	if err != nil {
+		return nil, err
+	}
+
+	var indexes []*IndexDescribe
+	for _, indexColumn := range indexColumns {
+		n := len(indexes)
+		if n == 0 || indexes[n-1].Name != indexColumn.IndexName {
+			indexes = append(indexes, &IndexDescribe{
+				Name:   indexColumn.IndexName,
+				Unique: indexColumn.IsUnique,
+			})
+			n++
+		}
+		indexes[n-1].Columns = append(indexes[n-1].Columns, indexColumn.ColumnName)
+	}
+
+	return &TableDescribe{
+		Name:        table,
+		Columns:     columnDescribes,
+		ForeignKeys: groupForeignKeys(foreignKeys),
+		Indexes:     indexes,
+	}, nil
+}
+
+func (src *PostgresSource) ListTables() ([]string, error) {
+	tables := []string{}
+	err := src.db.Select(&tables, `SELECT table_name FROM information_schema.tables
+		WHERE table_schema = current_schema() AND table_type = 'BASE TABLE'
+		ORDER BY table_name`)
+	if err != nil {
+		return nil, err
+	}
+
+	return tables, nil
+}
@@ -0,0 +1,222 @@
+package parser
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Relation is a relation of an object to another object, resolved from a
+// single column foreign key. Field is the column of the object owning the
+// relation and RelatedField is the matching column of the Related object.
+type Relation struct {
+	Name         string
+	Related      *Object
+	Field        *Field
+	RelatedField *Field
+}
+
+type foreignKeyColumn struct {
+	Name             string `db:"constraint_name"`
+	Column           string `db:"column_name"`
+	ReferencedTable  string `db:"referenced_table_name"`
+	ReferencedColumn string `db:"referenced_column_name"`
+}
+
+// AddRelation configures a relation from the column of the table to the
+// referenced column, for the relations that are not declared as foreign keys
+// on the schema.
+func (tp *ObjectParser) AddRelation(table, column, referencedTable, referencedColumn string) {
+	tp.relations[table] = append(tp.relations[table], &ForeignKeyDescribe{
+		Columns:           []string{column},
+		ReferencedTable:   referencedTable,
+		ReferencedColumns: []string{referencedColumn},
+	})
+}
+
+// ParseTables parses every given table and resolves their BelongsTo and
+// HasMany relations from the foreign keys between them. Foreign keys to tables
+// that are not given are ignored, as well as the multi column ones.
+func (tp *ObjectParser) ParseTables(tables []string) ([]*Object, error) {
+	var objs []*Object
+	objsByTable := make(map[string]*Object)
+	for _, table := range tables {
+		obj, err := tp.Parse(table)
+		if err != nil {
+			return nil, err
+		}
+		objs = append(objs, obj)
+		objsByTable[table] = obj
+	}
+
+	for _, obj := range objs {
+		foreignKeys := obj.foreignKeys
+		for _, configured := range tp.relations[obj.Table] {
+			if !hasForeignKey(foreignKeys, configured) {
+				foreignKeys = append(foreignKeys, configured)
+			}
+		}
+
+		for _, foreignKey := range foreignKeys {
+			if len(foreignKey.Columns) != 1 {
+				continue
+			}
+			related, ok := objsByTable[foreignKey.ReferencedTable]
+			if !ok {
+				continue
+			}
+
+			field := obj.field(foreignKey.Columns[0])
+			if field == nil {
+				return nil, fmt.Errorf("table '%s': unknown foreign key column '%s'",
+					obj.Table, foreignKey.Columns[0])
+			}
+
+			var relatedField *Field
+			switch {
+			case len(foreignKey.ReferencedColumns) == 1:
+				relatedField = related.field(foreignKey.ReferencedColumns[0])
+			case len(foreignKey.ReferencedColumns) == 0 && len(related.PrimaryKeys) == 1:
+				relatedField = related.PrimaryKeys[0]
+			}
+			if relatedField == nil {
+				continue
+			}
+
+			obj.BelongsTo = append(obj.BelongsTo, &Relation{
+				Name:         belongsToName(string(field.DBField), related),
+				Related:      related,
+				Field:        field,
+				RelatedField: relatedField,
+			})
+			related.HasMany = append(related.HasMany, &Relation{
+				Name:         obj.Name,
+				Related:      obj,
+				Field:        relatedField,
+				RelatedField: field,
+			})
+		}
+	}
+
+	for _, obj := range objs {
+		disambiguateRelations(obj)
+		obj.Relations = append(append(obj.Relations, obj.BelongsTo...), obj.HasMany...)
+	}
+
+	return objs, nil
+}
+
+func hasForeignKey(foreignKeys []*ForeignKeyDescribe, foreignKey *ForeignKeyDescribe) bool {
+	for _, declared := range foreignKeys {
+		if len(declared.Columns) == 1 &&
+			strings.EqualFold(declared.Columns[0], foreignKey.Columns[0]) &&
+			declared.ReferencedTable == foreignKey.ReferencedTable {
+			return true
+		}
+	}
+
+	return false
+}
+
+// reservedBelongsToNames and reservedHasManyNames are the relation names whose
+// accessors, Get{{Name}}{{Relation}} and Get{{Name}}{{Relation}}List, would
+// clash with the generated query methods.
+var (
+	reservedBelongsToNames = map[string]bool{
+		"Count": true, "List": true, "Page": true, "CursorList": true, "GroupList": true,
+	}
+	reservedHasManyNames = map[string]bool{
+		"Cursor": true, "Group": true,
+	}
+)
+
+// belongsToName names the relation after its foreign key column without the
+// id suffix, e.g. User for user_id, or after the related object otherwise or
+// when the column name is reserved.
+func belongsToName(column string, related *Object) string {
+	lowerColumn := strings.ToLower(column)
+	for _, suffix := range []string{"_id", "id"} {
+		if strings.HasSuffix(lowerColumn, suffix) && len(column) > len(suffix) {
+			name := snakeToCamel(strings.TrimSuffix(lowerColumn, suffix))
+			if !reservedBelongsToNames[name] {
+				return name
+			}
+			break
+		}
+	}
+
+	return related.Name
+}
+
+// disambiguateRelations suffixes the relation names shared by several
+// relations of the object, reserved, or taken by a field of the object, with
+// the column that distinguishes them, e.g. OrdersByBuyerId and
+// OrdersBySellerId. The names left clashing are then numbered, so that every
+// relation and field name of the object is unique.
+func disambiguateRelations(obj *Object) {
+	taken := make(map[string]bool)
+	for _, field := range obj.Fields {
+		taken[string(field.GoName)] = true
+	}
+
+	counts := make(map[string]int)
+	for _, relation := range obj.BelongsTo {
+		counts[relation.Name]++
+	}
+	for _, relation := range obj.HasMany {
+		counts[relation.Name]++
+	}
+
+	for _, relation := range obj.BelongsTo {
+		if counts[relation.Name] > 1 || taken[relation.Name] || reservedBelongsToNames[relation.Name] {
+			relation.Name += "By" + string(relation.Field.GoName)
+		}
+	}
+	for _, relation := range obj.HasMany {
+		if counts[relation.Name] > 1 || taken[relation.Name] || reservedHasManyNames[relation.Name] {
+			relation.Name += "By" + string(relation.RelatedField.GoName)
+		}
+	}
+
+	for _, relations := range [][]*Relation{obj.BelongsTo, obj.HasMany} {
+		for _, relation := range relations {
+			name := relation.Name
+			for n := 2; taken[name]; n++ {
+				name = fmt.Sprintf("%s%d", relation.Name, n)
+			}
+			relation.Name = name
+			taken[name] = true
+		}
+	}
+}
+
+// groupForeignKeys groups the columns of the foreign keys, ordered by their
+// constraint name and column position, into their constraints.
+func groupForeignKeys(columns []*foreignKeyColumn) []*ForeignKeyDescribe {
+	var foreignKeys []*ForeignKeyDescribe
+	for _, column := range columns {
+		n := len(foreignKeys)
+		if n == 0 || foreignKeys[n-1].Name != column.Name {
+			foreignKeys = append(foreignKeys, &ForeignKeyDescribe{
+				Name:            column.Name,
+				ReferencedTable: column.ReferencedTable,
+			})
+			n++
+		}
+
+		foreignKey := foreignKeys[n-1]
+		foreignKey.Columns = append(foreignKey.Columns, column.Column)
+		foreignKey.ReferencedColumns = append(foreignKey.ReferencedColumns, column.ReferencedColumn)
+	}
+
+	return foreignKeys
+}
+
+func (obj *Object) field(dbField string) *Field {
+	for _, field := range obj.Fields {
+		if strings.EqualFold(string(field.DBField), dbField) {
+			return field
+		}
+	}
+
+	return nil
+}
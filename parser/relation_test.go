@@ -0,0 +1,116 @@
+package parser
+
+import (
+	"reflect"
+	"testing"
+)
+
+// relationNames returns the names of the BelongsTo and HasMany relations of
+// every object, by table.
+func relationNames(objs []*Object) (map[string][]string, map[string][]string) {
+	belongsTo := make(map[string][]string)
+	hasMany := make(map[string][]string)
+	for _, obj := range objs {
+		for _, relation := range obj.BelongsTo {
+			belongsTo[obj.Table] = append(belongsTo[obj.Table], relation.Name)
+		}
+		for _, relation := range obj.HasMany {
+			hasMany[obj.Table] = append(hasMany[obj.Table], relation.Name)
+		}
+	}
+	return belongsTo, hasMany
+}
+
+func TestParseTablesRelationNames(t *testing.T) {
+	tests := []struct {
+		name      string
+		ddl       string
+		tables    []string
+		belongsTo map[string][]string
+		hasMany   map[string][]string
+	}{
+		{
+			name: "named after the column",
+			ddl: `CREATE TABLE users (id int PRIMARY KEY);
+			CREATE TABLE orders (id int PRIMARY KEY, user_id int REFERENCES users (id));`,
+			tables:    []string{"users", "orders"},
+			belongsTo: map[string][]string{"orders": {"User"}},
+			hasMany:   map[string][]string{"users": {"Orders"}},
+		},
+		{
+			name: "several relations to the same table",
+			ddl: `CREATE TABLE users (id int PRIMARY KEY);
+			CREATE TABLE orders (
+				id int PRIMARY KEY,
+				buyer_id int REFERENCES users (id),
+				seller_id int REFERENCES users (id)
+			);`,
+			tables:    []string{"users", "orders"},
+			belongsTo: map[string][]string{"orders": {"Buyer", "Seller"}},
+			hasMany:   map[string][]string{"users": {"OrdersByBuyerId", "OrdersBySellerId"}},
+		},
+		{
+			name: "reserved names",
+			ddl: `CREATE TABLE counts (id int PRIMARY KEY);
+			CREATE TABLE cursor (id int PRIMARY KEY, count_id int REFERENCES counts (id));`,
+			tables:    []string{"counts", "cursor"},
+			belongsTo: map[string][]string{"cursor": {"Counts"}},
+			hasMany:   map[string][]string{"counts": {"CursorByCountId"}},
+		},
+		{
+			name: "relation named after a field",
+			ddl: `CREATE TABLE author (id int PRIMARY KEY);
+			CREATE TABLE posts (
+				id int PRIMARY KEY,
+				author varchar(100),
+				author_id int REFERENCES author (id)
+			);`,
+			tables:    []string{"author", "posts"},
+			belongsTo: map[string][]string{"posts": {"AuthorByAuthorId"}},
+			hasMany:   map[string][]string{"author": {"Posts"}},
+		},
+		{
+			name: "belongs to and has many the same table",
+			ddl: `CREATE TABLE user (id int PRIMARY KEY, team_id int);
+			CREATE TABLE team (id int PRIMARY KEY, owner_id int REFERENCES user (id));
+			ALTER TABLE user ADD FOREIGN KEY (team_id) REFERENCES team (id);`,
+			tables:    []string{"user", "team"},
+			belongsTo: map[string][]string{"user": {"TeamByTeamId"}, "team": {"Owner"}},
+			hasMany:   map[string][]string{"user": {"TeamByOwnerId"}, "team": {"User"}},
+		},
+		{
+			name: "suffixed name taken by a field",
+			ddl: `CREATE TABLE users (id int PRIMARY KEY);
+			CREATE TABLE orders (
+				id int PRIMARY KEY,
+				user_by_user_id int,
+				user varchar(100),
+				user_id int REFERENCES users (id)
+			);`,
+			tables:    []string{"users", "orders"},
+			belongsTo: map[string][]string{"orders": {"UserByUserId2"}},
+			hasMany:   map[string][]string{"users": {"Orders"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			source := NewDDLSource(DialectMySQL)
+			if err := source.Exec(tt.ddl); err != nil {
+				t.Fatal(err)
+			}
+			objs, err := NewTableParser(source).ParseTables(tt.tables)
+			if err != nil {
+				t.Fatalf("ParseTables() error = %v", err)
+			}
+
+			belongsTo, hasMany := relationNames(objs)
+			if !reflect.DeepEqual(belongsTo, tt.belongsTo) {
+				t.Errorf("BelongsTo names = %q, want %q", belongsTo, tt.belongsTo)
+			}
+			if !reflect.DeepEqual(hasMany, tt.hasMany) {
+				t.Errorf("HasMany names = %q, want %q", hasMany, tt.hasMany)
+			}
+		})
+	}
+}
@@ -0,0 +1,183 @@
+package parser
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+)
+
+// SnapshotVersion is the version of the snapshot file format written by
+// WriteSnapshot.
+const SnapshotVersion = 1
+
+type snapshot struct {
+	Version int              `json:"version"`
+	Dialect string           `json:"dialect"`
+	Tables  []*snapshotTable `json:"tables"`
+}
+
+type snapshotTable struct {
+	Name        string                `json:"name"`
+	Columns     []*snapshotColumn     `json:"columns"`
+	ForeignKeys []*snapshotForeignKey `json:"foreign_keys,omitempty"`
+	Indexes     []*snapshotIndex      `json:"indexes,omitempty"`
+}
+
+type snapshotColumn struct {
+	Field   string  `json:"field"`
+	Type    string  `json:"type"`
+	Null    string  `json:"null"`
+	Key     string  `json:"key,omitempty"`
+	Default *string `json:"default,omitempty"`
+	Extra   string  `json:"extra,omitempty"`
+}
+
+type snapshotForeignKey struct {
+	Name              string   `json:"name,omitempty"`
+	Columns           []string `json:"columns"`
+	ReferencedTable   string   `json:"referenced_table"`
+	ReferencedColumns []string `json:"referenced_columns,omitempty"`
+}
+
+type snapshotIndex struct {
+	Name    string   `json:"name,omitempty"`
+	Unique  bool     `json:"unique,omitempty"`
+	Columns []string `json:"columns"`
+}
+
+// SnapshotSource describes tables from a snapshot file written by
+// WriteSnapshot, so the objects could be parsed without any database
+// connection.
+type SnapshotSource struct {
+	dialect string
+	tables  map[string]*TableDescribe
+}
+
+// WriteSnapshot writes the description of the given tables as a JSON snapshot.
+func WriteSnapshot(w io.Writer, source SchemaSource, tables []string) error {
+	snap := &snapshot{
+		Version: SnapshotVersion,
+		Dialect: source.Dialect(),
+	}
+	for _, table := range tables {
+		tableDescribe, err := source.DescribeTable(table)
+		if err != nil {
+			return err
+		}
+		if len(tableDescribe.Columns) == 0 {
+			return fmt.Errorf("table '%s' not found", table)
+		}
+
+		snapTable := &snapshotTable{Name: table}
+		for _, column := range tableDescribe.Columns {
+			snapColumn := &snapshotColumn{
+				Field: column.Field.String,
+				Type:  column.Type.String,
+				Null:  column.Null.String,
+				Key:   column.Key.String,
+				Extra: column.Extra.String,
+			}
+			if column.Default.Valid {
+				defaultValue := column.Default.String
+				snapColumn.Default = &defaultValue
+			}
+			snapTable.Columns = append(snapTable.Columns, snapColumn)
+		}
+		for _, foreignKey := range tableDescribe.ForeignKeys {
+			snapTable.ForeignKeys = append(snapTable.ForeignKeys, &snapshotForeignKey{
+				Name:              foreignKey.Name,
+				Columns:           foreignKey.Columns,
+				ReferencedTable:   foreignKey.ReferencedTable,
+				ReferencedColumns: foreignKey.ReferencedColumns,
+			})
+		}
+		for _, index := range tableDescribe.Indexes {
+			snapTable.Indexes = append(snapTable.Indexes, &snapshotIndex{
+				Name:    index.Name,
+				Unique:  index.Unique,
+				Columns: index.Columns,
+			})
+		}
+		snap.Tables = append(snap.Tables, snapTable)
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(snap)
+}
+
+func NewSnapshotSource(path string) (*SnapshotSource, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	snap := &snapshot{}
+	if err := json.NewDecoder(file).Decode(snap); err != nil {
+		return nil, fmt.Errorf("invalid snapshot '%s': %v", path, err)
+	}
+	if snap.Version < 1 || snap.Version > SnapshotVersion {
+		return nil, fmt.Errorf("unsupported snapshot version %d", snap.Version)
+	}
+	if _, err := DialectFromDriver(snap.Dialect); err != nil {
+		return nil, err
+	}
+
+	src := &SnapshotSource{
+		dialect: snap.Dialect,
+		tables:  make(map[string]*TableDescribe),
+	}
+	for _, snapTable := range snap.Tables {
+		tableDescribe := &TableDescribe{Name: snapTable.Name}
+		for _, snapColumn := range snapTable.Columns {
+			column := &ColumnDescribe{
+				Field: nullString(snapColumn.Field),
+				Type:  nullString(snapColumn.Type),
+				Null:  nullString(snapColumn.Null),
+				Key:   nullString(snapColumn.Key),
+				Extra: nullString(snapColumn.Extra),
+			}
+			if snapColumn.Default != nil {
+				column.Default = nullString(*snapColumn.Default)
+			}
+			tableDescribe.Columns = append(tableDescribe.Columns, column)
+		}
+		for _, snapForeignKey := range snapTable.ForeignKeys {
+			tableDescribe.ForeignKeys = append(tableDescribe.ForeignKeys, &ForeignKeyDescribe{
+				Name:              snapForeignKey.Name,
+				Columns:           snapForeignKey.Columns,
+				ReferencedTable:   snapForeignKey.ReferencedTable,
+				ReferencedColumns: snapForeignKey.ReferencedColumns,
+			})
+		}
+		for _, snapIndex := range snapTable.Indexes {
+			tableDescribe.Indexes = append(tableDescribe.Indexes, &IndexDescribe{
+				Name:    snapIndex.Name,
+				Unique:  snapIndex.Unique,
+				Columns: snapIndex.Columns,
+			})
+		}
+		src.tables[snapTable.Name] = tableDescribe
+	}
+
+	return src, nil
+}
+
+func (src *SnapshotSource) Dialect() string {
+	return src.dialect
+}
+
+func (src *SnapshotSource) DescribeTable(table string) (*TableDescribe, error) {
+	tableDescribe, ok := src.tables[table]
+	if !ok {
+		return nil, fmt.Errorf("table '%s' not found in snapshot", table)
+	}
+
+	return tableDescribe, nil
+}
+
+func (src *SnapshotSource) ListTables() ([]string, error) {
+	return sortedTables(src.tables), nil
+}
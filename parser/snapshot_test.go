@@ -0,0 +1,141 @@
+package parser
+
+import (
+	"bytes"
+	"database/sql"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSnapshotRoundTrip(t *testing.T) {
+	src := NewDDLSource(DialectPostgres)
+	err := src.Exec(`
+	CREATE TABLE users (
+		id serial PRIMARY KEY,
+		email varchar(255) NOT NULL UNIQUE,
+		nickname text DEFAULT '',
+		bio text DEFAULT NULL,
+		note text
+	);
+	CREATE TABLE sessions (
+		id serial PRIMARY KEY,
+		user_id integer NOT NULL REFERENCES users (id),
+		token text NOT NULL
+	);
+	CREATE INDEX idx_token ON sessions (token);`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var snap bytes.Buffer
+	if err := WriteSnapshot(&snap, src, []string{"users", "sessions"}); err != nil {
+		t.Fatalf("WriteSnapshot() error = %v", err)
+	}
+	path := filepath.Join(t.TempDir(), "schema.json")
+	if err := os.WriteFile(path, snap.Bytes(), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	snapSrc, err := NewSnapshotSource(path)
+	if err != nil {
+		t.Fatalf("NewSnapshotSource() error = %v", err)
+	}
+	if snapSrc.Dialect() != DialectPostgres {
+		t.Errorf("Dialect() = %q, want %q", snapSrc.Dialect(), DialectPostgres)
+	}
+	if tables, _ := snapSrc.ListTables(); !reflect.DeepEqual(tables, []string{"sessions", "users"}) {
+		t.Errorf("ListTables() = %q", tables)
+	}
+	for _, table := range []string{"users", "sessions"} {
+		want, _ := src.DescribeTable(table)
+		got, err := snapSrc.DescribeTable(table)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("DescribeTable(%q) = %+v, want %+v", table, got, want)
+		}
+	}
+
+	// an empty default is kept apart from no default
+	users, _ := snapSrc.DescribeTable("users")
+	defaults := map[string]sql.NullString{
+		"nickname": {String: "", Valid: true},
+		"bio":      {},
+		"note":     {},
+	}
+	for _, column := range users.Columns {
+		if want, ok := defaults[column.Field.String]; ok && column.Default != want {
+			t.Errorf("%s default = %+v, want %+v", column.Field.String, column.Default, want)
+		}
+	}
+	if _, err := snapSrc.DescribeTable("orders"); err == nil {
+		t.Error("DescribeTable() found a table missing from the snapshot")
+	}
+}
+
+func TestWriteSnapshotErrors(t *testing.T) {
+	src := NewDDLSource(DialectMySQL)
+	if err := src.Exec(`CREATE TABLE empty ();`); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, table := range []string{"missing", "empty"} {
+		t.Run(table, func(t *testing.T) {
+			var snap bytes.Buffer
+			err := WriteSnapshot(&snap, src, []string{table})
+			if err == nil || !strings.Contains(err.Error(), "table '"+table+"' not found") {
+				t.Errorf("WriteSnapshot() error = %v, want table '%s' not found", err, table)
+			}
+		})
+	}
+}
+
+func TestNewSnapshotSourceErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		snapshot string
+		wantErr  string
+	}{
+		{
+			name:     "invalid json",
+			snapshot: `{"version": 1,`,
+			wantErr:  "invalid snapshot",
+		},
+		{
+			name:     "missing version",
+			snapshot: `{"dialect": "mysql", "tables": []}`,
+			wantErr:  "unsupported snapshot version 0",
+		},
+		{
+			name:     "newer version",
+			snapshot: `{"version": 2, "dialect": "mysql", "tables": []}`,
+			wantErr:  "unsupported snapshot version 2",
+		},
+		{
+			name:     "unknown dialect",
+			snapshot: `{"version": 1, "dialect": "oracle", "tables": []}`,
+			wantErr:  "unsupported driver 'oracle'",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "schema.json")
+			if err := os.WriteFile(path, []byte(tt.snapshot), 0644); err != nil {
+				t.Fatal(err)
+			}
+			_, err := NewSnapshotSource(path)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("NewSnapshotSource() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+
+	if _, err := NewSnapshotSource(filepath.Join(t.TempDir(), "missing.json")); err == nil {
+		t.Error("NewSnapshotSource() succeeded on a missing file")
+	}
+}
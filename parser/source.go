@@ -0,0 +1,32 @@
+package parser
+
+import (
+	"github.com/jmoiron/sqlx"
+)
+
+// SchemaSource provides the table descriptions that objects are parsed from,
+// e.g. a live database connection.
+type SchemaSource interface {
+	// Dialect returns the SQL dialect of the described tables.
+	Dialect() string
+	// DescribeTable returns the columns description of the given table.
+	DescribeTable(table string) (*TableDescribe, error)
+}
+
+// NewDBSource returns the SchemaSource that introspects the given live
+// database connection, resolved from its driver name.
+func NewDBSource(db *sqlx.DB) (SchemaSource, error) {
+	dialect, err := DialectFromDriver(db.DriverName())
+	if err != nil {
+		return nil, err
+	}
+
+	switch dialect {
+	case DialectPostgres:
+		return NewPostgresSource(db), nil
+	case DialectSQLite:
+		return NewSQLiteSource(db), nil
+	default:
+		return NewMySQLSource(db), nil
+	}
+}
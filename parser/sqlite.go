@@ -0,0 +1,227 @@
+package parser
+
+import (
+	"database/sql"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type sqliteColumn struct {
+	Cid     int            `db:"cid"`
+	Name    string         `db:"name"`
+	Type    string         `db:"type"`
+	NotNull bool           `db:"notnull"`
+	Default sql.NullString `db:"dflt_value"`
+	Pk      int            `db:"pk"`
+}
+
+type sqliteIndex struct {
+	Seq     int    `db:"seq"`
+	Name    string `db:"name"`
+	Unique  bool   `db:"unique"`
+	Origin  string `db:"origin"`
+	Partial bool   `db:"partial"`
+}
+
+type sqliteIndexColumn struct {
+	Seqno int            `db:"seqno"`
+	Cid   int            `db:"cid"`
+	Name  sql.NullString `db:"name"`
+}
+
+type sqliteForeignKey struct {
+	Id       int            `db:"id"`
+	Seq      int            `db:"seq"`
+	Table    string         `db:"table"`
+	From     string         `db:"from"`
+	To       sql.NullString `db:"to"`
+	OnUpdate string         `db:"on_update"`
+	OnDelete string         `db:"on_delete"`
+	Match    string         `db:"match"`
+}
+
+// SQLiteSource describes tables from a live SQLite connection.
+type SQLiteSource struct {
+	db *sqlx.DB
+}
+
+func NewSQLiteSource(db *sqlx.DB) *SQLiteSource {
+	return &SQLiteSource{
+		db: db,
+	}
+}
+
+func (src *SQLiteSource) Dialect() string {
+	return DialectSQLite
+}
+
+func (src *SQLiteSource) DescribeTable(table string) (*TableDescribe, error) {
+	columns := []*sqliteColumn{}
+	err := src.db.Select(&columns, fmt.Sprintf(`PRAGMA table_info("%s")`, table))
+	if err != nil {
+		return nil, err
+	}
+
+	keys, indexes, err := describeSQLiteIndexes(src.db, table)
+	if err != nil {
+		return nil, err
+	}
+
+	var primaryKeys int
+	for _, column := range columns {
+		if column.Pk > 0 {
+			primaryKeys++
+		}
+	}
+
+	var columnDescribes []*ColumnDescribe
+	for _, column := range columns {
+		null := "YES"
+		if column.NotNull || column.Pk > 0 {
+			null = "NO"
+		}
+
+		key := keys[column.Name]
+		var extra string
+		if column.Pk > 0 {
+			key = "PRI"
+			// a single INTEGER PRIMARY KEY column is an alias of the rowid
+			if primaryKeys == 1 && strings.EqualFold(column.Type, "integer") {
+				extra = "auto_increment"
+			}
+		}
+
+		columnDescribes = append(columnDescribes, &ColumnDescribe{
+			Field:   sql.NullString{String: column.Name, Valid: true},
+			Type:    sql.NullString{String: column.Type, Valid: true},
+			Null:    sql.NullString{String: null, Valid: true},
+			Key:     sql.NullString{String: key, Valid: true},
+			Default: column.Default,
+			Extra:   sql.NullString{String: extra, Valid: true},
+		})
+	}
+
+	foreignKeys, err := describeSQLiteForeignKeys(src.db, table)
+	if err != nil {
+		return nil, err
+	}
+
+	return &TableDescribe{
+		Name:        table,
+		Columns:     columnDescribes,
+		ForeignKeys: foreignKeys,
+		Indexes:     indexes,
+	}, nil
+}
+
+func (src *SQLiteSource) ListTables() ([]string, error) {
+	tables := []string{}
+	err := src.db.Select(&tables, `SELECT name FROM sqlite_master
+		WHERE type = 'table' AND name NOT LIKE 'sqlite_%'
+		ORDER BY name`)
+	if err != nil {
+		return nil, err
+	}
+
+	return tables, nil
+}
+
+// describeSQLiteIndexes resolves the secondary indexes of the table, and the
+// UNI and MUL keys of its columns the same way MySQL reports them on DESCRIBE.
+func describeSQLiteIndexes(db *sqlx.DB, table string) (map[string]string, []*IndexDescribe, error) {
+	indexes := []*sqliteIndex{}
+	err := db.Select(&indexes, fmt.Sprintf(`PRAGMA index_list("%s")`, table))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	keys := make(map[string]string)
+	var indexDescribes []*IndexDescribe
+	for _, index := range indexes {
+		if index.Origin == "pk" {
+			continue
+		}
+
+		indexColumns := []*sqliteIndexColumn{}
+		err := db.Select(&indexColumns, fmt.Sprintf(`PRAGMA index_info("%s")`, index.Name))
+		if err != nil {
+			return nil, nil, err
+		}
+		if len(indexColumns) == 0 || !indexColumns[0].Name.Valid {
+			continue
+		}
+
+		column := indexColumns[0].Name.String
+		if index.Unique && len(indexColumns) == 1 {
+			keys[column] = "UNI"
+		} else if keys[column] == "" {
+			keys[column] = "MUL"
+		}
+
+		if index.Partial {
+			continue
+		}
+		indexDescribe := &IndexDescribe{
+			Name:   index.Name,
+			Unique: index.Unique,
+		}
+		for _, indexColumn := range indexColumns {
+			// expression indexes have no column name
+			if !indexColumn.Name.Valid {
+				indexDescribe = nil
+				break
+			}
+			indexDescribe.Columns = append(indexDescribe.Columns, indexColumn.Name.String)
+		}
+		if indexDescribe != nil {
+			indexDescribes = append(indexDescribes, indexDescribe)
+		}
+	}
+
+	return keys, indexDescribes, nil
+}
+
+// describeSQLiteForeignKeys resolves the foreign keys of the table. SQLite does
+// not name them, so they are named after their id.
+func describeSQLiteForeignKeys(db *sqlx.DB, table string) ([]*ForeignKeyDescribe, error) {
+	foreignKeys := []*sqliteForeignKey{}
+	err := db.Select(&foreignKeys, fmt.Sprintf(`PRAGMA foreign_key_list("%s")`, table))
+	if err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(foreignKeys, func(i, j int) bool {
+		if foreignKeys[i].Id != foreignKeys[j].Id {
+			return foreignKeys[i].Id < foreignKeys[j].Id
+		}
+		return foreignKeys[i].Seq < foreignKeys[j].Seq
+	})
+
+	var columns []*foreignKeyColumn
+	implicit := make(map[string]bool)
+	for _, foreignKey := range foreignKeys {
+		name := fmt.Sprintf("%s_fk_%d", table, foreignKey.Id)
+		columns = append(columns, &foreignKeyColumn{
+			Name:             name,
+			Column:           foreignKey.From,
+			ReferencedTable:  foreignKey.Table,
+			ReferencedColumn: foreignKey.To.String,
+		})
+		if !foreignKey.To.Valid {
+			implicit[name] = true
+		}
+	}
+
+	describes := groupForeignKeys(columns)
+	for _, describe := range describes {
+		// REFERENCES without columns references the primary key
+		if implicit[describe.Name] {
+			describe.ReferencedColumns = nil
+		}
+	}
+
+	return describes, nil
+}
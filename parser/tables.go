@@ -0,0 +1,92 @@
+package parser
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// TableLister is implemented by the schema sources that are able to list all of
+// their tables.
+type TableLister interface {
+	ListTables() ([]string, error)
+}
+
+// ResolveTables resolves the tables to generate. The "*" table stands for all
+// of the source tables. The tables are then kept when they match any of the
+// includes patterns, if any, and none of the excludes patterns. A pattern is
+// either a glob or a regular expression enclosed by slashes, e.g. /^tmp_/.
+func ResolveTables(source SchemaSource, tables, includes, excludes []string) ([]string, error) {
+	if len(tables) == 1 && tables[0] == "*" {
+		lister, ok := source.(TableLister)
+		if !ok {
+			return nil, fmt.Errorf("unable to list the tables of the %T schema source", source)
+		}
+
+		var err error
+		tables, err = lister.ListTables()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var resolved []string
+	for _, table := range tables {
+		included := len(includes) == 0
+		for _, pattern := range includes {
+			matched, err := matchTable(pattern, table)
+			if err != nil {
+				return nil, err
+			}
+			if matched {
+				included = true
+				break
+			}
+		}
+
+		for _, pattern := range excludes {
+			matched, err := matchTable(pattern, table)
+			if err != nil {
+				return nil, err
+			}
+			if matched {
+				included = false
+				break
+			}
+		}
+
+		if included {
+			resolved = append(resolved, table)
+		}
+	}
+
+	return resolved, nil
+}
+
+func sortedTables(tableDescribes map[string]*TableDescribe) []string {
+	var tables []string
+	for table := range tableDescribes {
+		tables = append(tables, table)
+	}
+	sort.Strings(tables)
+
+	return tables
+}
+
+func matchTable(pattern, table string) (bool, error) {
+	if len(pattern) > 1 && strings.HasPrefix(pattern, "/") && strings.HasSuffix(pattern, "/") {
+		matched, err := regexp.MatchString(pattern[1:len(pattern)-1], table)
+		if err != nil {
+			return false, fmt.Errorf("invalid table pattern '%s': %v", pattern, err)
+		}
+		return matched, nil
+	}
+
+	matched, err := path.Match(pattern, table)
+	if err != nil {
+		return false, fmt.Errorf("invalid table pattern '%s': %v", pattern, err)
+	}
+	return matched, nil
+}
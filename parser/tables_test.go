@@ -0,0 +1,169 @@
+package parser
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestResolveTables(t *testing.T) {
+	source := NewDDLSource(DialectMySQL)
+	if err := source.Exec(`
+	CREATE TABLE users (id int PRIMARY KEY);
+	CREATE TABLE user_roles (id int PRIMARY KEY);
+	CREATE TABLE orders (id int PRIMARY KEY);
+	CREATE TABLE tmp_orders (id int PRIMARY KEY);
+	CREATE TABLE schema_migrations (version bigint PRIMARY KEY);`); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		tables   []string
+		includes []string
+		excludes []string
+		want     []string
+	}{
+		{
+			name:   "all tables",
+			tables: []string{"*"},
+			want:   []string{"orders", "schema_migrations", "tmp_orders", "user_roles", "users"},
+		},
+		{
+			name:   "listed tables",
+			tables: []string{"users", "orders"},
+			want:   []string{"users", "orders"},
+		},
+		{
+			name:     "include globs",
+			tables:   []string{"*"},
+			includes: []string{"user*", "orders"},
+			want:     []string{"orders", "user_roles", "users"},
+		},
+		{
+			name:     "exclude globs",
+			tables:   []string{"*"},
+			excludes: []string{"schema_migrations", "tmp_*"},
+			want:     []string{"orders", "user_roles", "users"},
+		},
+		{
+			name:     "include regex",
+			tables:   []string{"*"},
+			includes: []string{"/orders$/"},
+			want:     []string{"orders", "tmp_orders"},
+		},
+		{
+			name:     "exclude wins over include",
+			tables:   []string{"*"},
+			includes: []string{"*orders"},
+			excludes: []string{"/^tmp_/"},
+			want:     []string{"orders"},
+		},
+		{
+			name:     "listed tables are filtered",
+			tables:   []string{"users", "tmp_orders"},
+			excludes: []string{"tmp_*"},
+			want:     []string{"users"},
+		},
+		{
+			name:     "nothing matched",
+			tables:   []string{"*"},
+			includes: []string{"accounts"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ResolveTables(source, tt.tables, tt.includes, tt.excludes)
+			if err != nil {
+				t.Fatalf("ResolveTables() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ResolveTables() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// describeOnlySource is a schema source unable to list its tables.
+type describeOnlySource struct{}
+
+func (describeOnlySource) Dialect() string { return DialectMySQL }
+
+func (describeOnlySource) DescribeTable(tableName string) (*TableDescribe, error) {
+	return &TableDescribe{}, nil
+}
+
+func TestResolveTablesErrors(t *testing.T) {
+	source := NewDDLSource(DialectMySQL)
+	if err := source.Exec(`CREATE TABLE users (id int PRIMARY KEY);`); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		source   SchemaSource
+		tables   []string
+		includes []string
+		excludes []string
+		wantErr  string
+	}{
+		{
+			name:    "all tables of a non lister",
+			source:  describeOnlySource{},
+			tables:  []string{"*"},
+			wantErr: "unable to list the tables",
+		},
+		{
+			name:     "invalid include glob",
+			source:   source,
+			tables:   []string{"*"},
+			includes: []string{"[users"},
+			wantErr:  "invalid table pattern '[users'",
+		},
+		{
+			name:     "invalid exclude regex",
+			source:   source,
+			tables:   []string{"*"},
+			excludes: []string{"/(users/"},
+			wantErr:  "invalid table pattern '/(users/'",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ResolveTables(tt.source, tt.tables, tt.includes, tt.excludes)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("ResolveTables() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestMatchTable(t *testing.T) {
+	tests := []struct {
+		pattern string
+		table   string
+		want    bool
+	}{
+		{pattern: "users", table: "users", want: true},
+		{pattern: "users", table: "users_copy"},
+		{pattern: "tmp_*", table: "tmp_orders", want: true},
+		{pattern: "tmp_?", table: "tmp_ab"},
+		{pattern: "/^tmp_/", table: "tmp_orders", want: true},
+		{pattern: "/^tmp_/", table: "orders_tmp_"},
+		{pattern: "/", table: "/", want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.pattern+" "+tt.table, func(t *testing.T) {
+			got, err := matchTable(tt.pattern, tt.table)
+			if err != nil {
+				t.Fatalf("matchTable() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("matchTable(%q, %q) = %v, want %v", tt.pattern, tt.table, got, tt.want)
+			}
+		})
+	}
+}
@@ -6,34 +6,22 @@ func (tp *TemplateParser) ParseRepoCommandTmpl() (string, error) {
 		Insert{{.Name}}List(ctx context.Context, {{.PrivateName}}List {{.ModelPackage}}{{.Name}}List) (*InsertResult, error)
 		Insert{{.Name}}(ctx context.Context, {{.PrivateName}} *{{.ModelPackage}}{{.Name}}) (*InsertResult, error)
 		Update{{.Name}}ByFilter(ctx context.Context, {{.PrivateName}} *{{.ModelPackage}}{{.Name}}, filter Filter, updatedFields ...{{.Name}}Field) error
-		Update{{.Name}}(ctx context.Context, {{.PrivateName}} *{{.ModelPackage}}{{.Name}}, {{.IdName}} {{.IdType}}, updatedFields ...{{.Name}}Field) error
 		Delete{{.Name}}List(ctx context.Context, filter Filter) error
+		{{if .ConflictFields}}Upsert{{.Name}}List(ctx context.Context, {{.PrivateName}}List {{.ModelPackage}}{{.Name}}List, updatedFields ...{{.Name}}Field) (*InsertResult, error)
+		Upsert{{.Name}}(ctx context.Context, {{.PrivateName}} *{{.ModelPackage}}{{.Name}}, updatedFields ...{{.Name}}Field) (*InsertResult, error)
+		{{end}}		{{if .CompositeKey}}Update{{.Name}}(ctx context.Context, {{.PrivateName}} *{{.ModelPackage}}{{.Name}}, key {{.Name}}Key, updatedFields ...{{.Name}}Field) error
+		Delete{{.Name}}(ctx context.Context, key {{.Name}}Key) error
+		{{else if .HasPrimaryKey}}Update{{.Name}}(ctx context.Context, {{.PrivateName}} *{{.ModelPackage}}{{.Name}}, {{.IdName}} {{.IdType}}, updatedFields ...{{.Name}}Field) error
 		Delete{{.Name}}(ctx context.Context, {{.IdName}} {{.IdType}}) error
+		{{end}}
 	}
 
 	type Repository{{.Name}}CommandImpl struct {
-		db   *sqlx.DB
-		tx   *sqlx.Tx
+		db   DBTX
 	}
 
 	func(repo *Repository{{.Name}}CommandImpl) Insert{{.Name}}List(ctx context.Context, {{.PrivateName}}List {{.ModelPackage}}{{.Name}}List) (*InsertResult, error) {
-		table := "{{.Backtick}}{{.Table}}{{.Backtick}}"
-		command := fmt.Sprintf({{.Backtick}}INSERT INTO %s ({{.DBFieldsSeperatedCommas}}) VALUES
-		{{.Backtick}}, table)
-
-		var (
-			placeholders []string
-			args   []interface{}
-		)
-		for _, {{.PrivateName}} := range {{.PrivateName}}List {
-			placeholders = append(placeholders, {{.Backtick}}({{.PlaceholdersSeparatedCommas}}){{.Backtick}})
-			args = append(args, {{range .Fields}}{{if .AutoIncrement}}
-				{{else}}{{.ObjectPrivateName}}.{{.GoName}},
-				{{end}}{{end}}
-			)
-		}
-		command += strings.Join(placeholders, ",")
-		
+		command, args := buildInsert{{.Name}}Command({{.PrivateName}}List)
 		sqlResult, err := repo.exec(ctx, command, args)
 		if err != nil {
 			return nil, err
@@ -46,65 +34,117 @@ func (tp *TemplateParser) ParseRepoCommandTmpl() (string, error) {
 		return repo.Insert{{.Name}}List(ctx, {{.ModelPackage}}{{.Name}}List{{.OpenBracket}}{{.PrivateName}}{{.CloseBracket}})
 	}
 
-	func(repo *Repository{{.Name}}CommandImpl) Update{{.Name}}ByFilter(ctx context.Context, {{.PrivateName}} *{{.ModelPackage}}{{.Name}}, filter Filter, updatedFields ...{{.Name}}Field) error {
-		table := "{{.Backtick}}{{.Table}}{{.Backtick}}"
+	{{if .ConflictFields}}// Upsert{{.Name}}List inserts the rows, or updates the updated fields of the
+	// rows conflicting on {{range $i, $field := .ConflictFields}}{{if $i}}, {{end}}{{$field.DBField}}{{end}}. Every inserted field but the
+	// conflicting ones is updated by default.
+	func(repo *Repository{{.Name}}CommandImpl) Upsert{{.Name}}List(ctx context.Context, {{.PrivateName}}List {{.ModelPackage}}{{.Name}}List, updatedFields ...{{.Name}}Field) (*InsertResult, error) {
+		if len(updatedFields) == 0 {
+			updatedFields = []{{.Name}}Field{ {{range .UpsertFields}}"{{.DBField}}", {{end}} }
+		}
+
+		var updates []string
+		for _, field := range updatedFields {
+			switch field {
+			{{range .Fields}}{{if not .AutoIncrement}}case "{{.DBField}}":
+				updates = append(updates, "{{.QuotedDBField}} = {{if eq $.Dialect "mysql"}}VALUES({{.QuotedDBField}}){{else}}EXCLUDED.{{.QuotedDBField}}{{end}}")
+			{{end}}{{end}}}
+		}
+
+		command, args := buildInsert{{.Name}}Command({{.PrivateName}}List)
+		{{if eq .Dialect "mysql"}}if len(updates) == 0 {
+			updates = append(updates, "{{(index .ConflictFields 0).QuotedDBField}} = {{(index .ConflictFields 0).QuotedDBField}}")
+		}
+		command += " ON DUPLICATE KEY UPDATE " + strings.Join(updates, ",")
+		{{else}}command += " ON CONFLICT ({{range $i, $field := .ConflictFields}}{{if $i}}, {{end}}{{$field.QuotedDBField}}{{end}}) DO "
+		if len(updates) == 0 {
+			command += "NOTHING"
+		} else {
+			command += "UPDATE SET " + strings.Join(updates, ",")
+		}
+		{{end}}
+		sqlResult, err := repo.exec(ctx, command, args)
+		if err != nil {
+			return nil, err
+		}
+
+		return &InsertResult{Result: sqlResult}, nil
+	}
+
+	func(repo *Repository{{.Name}}CommandImpl) Upsert{{.Name}}(ctx context.Context, {{.PrivateName}} *{{.ModelPackage}}{{.Name}}, updatedFields ...{{.Name}}Field) (*InsertResult, error) {
+		return repo.Upsert{{.Name}}List(ctx, {{.ModelPackage}}{{.Name}}List{{.OpenBracket}}{{.PrivateName}}{{.CloseBracket}}, updatedFields...)
+	}
+
+	{{end}}func(repo *Repository{{.Name}}CommandImpl) Update{{.Name}}ByFilter(ctx context.Context, {{.PrivateName}} *{{.ModelPackage}}{{.Name}}, filter Filter, updatedFields ...{{.Name}}Field) error {
+		if err := filterErr(filter); err != nil {
+			return err
+		}
+		if filter == nil || filter.Query() == "" {
+			return errors.New("{{.LowerName}} update by filter needs a non empty filter")
+		}
+		table := "{{.QuotedTable}}"
 		updatedFieldQuery, values := buildUpdateFields{{.Name}}Query(updatedFields, {{.PrivateName}})
-		command := fmt.Sprintf({{.Backtick}}UPDATE %s 
-			SET %s 
-		WHERE %s
-		{{.Backtick}}, table, strings.Join(updatedFieldQuery, ","), filter.Query())
+		command := fmt.Sprintf("UPDATE %s SET %s WHERE %s", table, strings.Join(updatedFieldQuery, ","), filter.Query())
 		values = append(values, filter.Values()...)
 		_, err := repo.exec(ctx, command, values)
 		return err
 	}
 
-	func(repo *Repository{{.Name}}CommandImpl) Update{{.Name}}(ctx context.Context, {{.PrivateName}} *{{.ModelPackage}}{{.Name}}, {{.IdName}} {{.IdType}}, updatedFields ...{{.Name}}Field) error {
+	{{if .CompositeKey}}func(repo *Repository{{.Name}}CommandImpl) Update{{.Name}}(ctx context.Context, {{.PrivateName}} *{{.ModelPackage}}{{.Name}}, key {{.Name}}Key, updatedFields ...{{.Name}}Field) error {
+		updatedFieldQuery, values := buildUpdateFields{{.Name}}Query(updatedFields, {{.PrivateName}})
+		table := "{{.QuotedTable}}"
+		command := fmt.Sprintf("UPDATE %s SET %s WHERE {{.PrimaryKeysQuery}}", table, strings.Join(updatedFieldQuery, ","))
+		values = append(values, {{range .PrimaryKeys}}key.{{.GoName}}, {{end}})
+		_, err := repo.exec(ctx, command, values)
+		return err
+	}
+	{{else if .HasPrimaryKey}}func(repo *Repository{{.Name}}CommandImpl) Update{{.Name}}(ctx context.Context, {{.PrivateName}} *{{.ModelPackage}}{{.Name}}, {{.IdName}} {{.IdType}}, updatedFields ...{{.Name}}Field) error {
 		updatedFieldQuery, values := buildUpdateFields{{.Name}}Query(updatedFields, {{.PrivateName}})
-		table := "{{.Backtick}}{{.Table}}{{.Backtick}}"
-		command := fmt.Sprintf({{.Backtick}}UPDATE %s 
-			SET %s 
-		WHERE {{.IdDBName}} = ?
-		{{.Backtick}}, table, strings.Join(updatedFieldQuery, ","))
+		table := "{{.QuotedTable}}"
+		command := fmt.Sprintf("UPDATE %s SET %s WHERE {{.PrimaryKeysQuery}}", table, strings.Join(updatedFieldQuery, ","))
 		values = append(values, {{.IdName}})
 		_, err := repo.exec(ctx, command, values)
 		return err
 	}
+	{{end}}
 
 	func(repo *Repository{{.Name}}CommandImpl) Delete{{.Name}}List(ctx context.Context, filter Filter) error {
-		command := "DELETE FROM {{.Backtick}}{{.Table}}{{.Backtick}} WHERE "+filter.Query()
+		if err := filterErr(filter); err != nil {
+			return err
+		}
+		if filter == nil || filter.Query() == "" {
+			return errors.New("{{.LowerName}} delete list needs a non empty filter")
+		}
+		command := "DELETE FROM {{.QuotedTable}} WHERE "+filter.Query()
 		_, err := repo.exec(ctx, command, filter.Values())
 		return err
 	}
 
-	func(repo *Repository{{.Name}}CommandImpl) Delete{{.Name}}(ctx context.Context, {{.IdName}} {{.IdType}}) error {
-		command := "DELETE FROM {{.Backtick}}{{.Table}}{{.Backtick}} WHERE {{.IdDBName}} = ?"
+	{{if .CompositeKey}}func(repo *Repository{{.Name}}CommandImpl) Delete{{.Name}}(ctx context.Context, key {{.Name}}Key) error {
+		command := "DELETE FROM {{.QuotedTable}} WHERE {{.PrimaryKeysQuery}}"
+		_, err := repo.exec(ctx, command, []interface{{.OpenBracket}}{{.CloseBracket}}{{.OpenBracket}}{{range .PrimaryKeys}}key.{{.GoName}}, {{end}}{{.CloseBracket}})
+		return err
+	}
+	{{else if .HasPrimaryKey}}func(repo *Repository{{.Name}}CommandImpl) Delete{{.Name}}(ctx context.Context, {{.IdName}} {{.IdType}}) error {
+		command := "DELETE FROM {{.QuotedTable}} WHERE {{.PrimaryKeysQuery}}"
 		_, err := repo.exec(ctx, command, []interface{{.OpenBracket}}{{.CloseBracket}}{{.OpenBracket}}{{.IdName}}{{.CloseBracket}})
 		return err
 	}
+	{{end}}
 
-	func NewRepo{{.Name}}Command(db *sqlx.DB) Repository{{.Name}}Command {
+	func NewRepo{{.Name}}Command(db DBTX) Repository{{.Name}}Command {
 		return &Repository{{.Name}}CommandImpl{
 			db: db,
 		}
 	}
 
-	func NewRepo{{.Name}}CommandFromTx(tx *sqlx.Tx) Repository{{.Name}}Command {
+	func NewRepo{{.Name}}CommandFromTx(tx DBTX) Repository{{.Name}}Command {
 		return &Repository{{.Name}}CommandImpl{
-			tx: tx,
+			db: tx,
 		}
 	}
 
 	func(repo *Repository{{.Name}}CommandImpl) exec(ctx context.Context, command string, args []interface{}) (sql.Result, error) {
-		var (
-			stmt *sqlx.Stmt
-			err  error
-		)
-		if repo.tx != nil {
-			stmt, err = repo.tx.PreparexContext(ctx, command)
-		} else {
-			stmt, err = repo.db.PreparexContext(ctx, command)
-		}
-	
+		stmt, err := repo.db.PreparexContext(ctx, rebind(command))
 		if err != nil {
 			return nil, err
 		}
@@ -118,6 +158,25 @@ func (tp *TemplateParser) ParseRepoCommandTmpl() (string, error) {
 		return result, nil
 	}
 
+	func buildInsert{{.Name}}Command({{.PrivateName}}List {{.ModelPackage}}{{.Name}}List) (string, []interface{}) {
+		table := "{{.QuotedTable}}"
+		command := fmt.Sprintf("INSERT INTO %s ({{.DBFieldsSeperatedCommas}}) VALUES ", table)
+
+		var (
+			placeholders []string
+			args   []interface{}
+		)
+		for _, {{.PrivateName}} := range {{.PrivateName}}List {
+			placeholders = append(placeholders, {{.Backtick}}({{.PlaceholdersSeparatedCommas}}){{.Backtick}})
+			args = append(args, {{range .Fields}}{{if .AutoIncrement}}
+				{{else}}{{.ObjectPrivateName}}.{{.GoName}},
+				{{end}}{{end}}
+			)
+		}
+		command += strings.Join(placeholders, ",")
+		return command, args
+	}
+
 	func buildUpdateFields{{.Name}}Query(updatedFields {{.Name}}FieldList, {{.PrivateName}} *{{.ModelPackage}}{{.Name}}) ([]string, []interface{}) {
 		var (
 			updatedFieldsQuery []string
@@ -127,7 +186,7 @@ func (tp *TemplateParser) ParseRepoCommandTmpl() (string, error) {
 		for _, field := range updatedFields {
 			switch field {
 			{{range .Fields}} case "{{.DBField}}":
-				updatedFieldsQuery = append(updatedFieldsQuery, "{{.DBField}} = ?")
+				updatedFieldsQuery = append(updatedFieldsQuery, "{{.QuotedDBField}} = ?")
 				args = append(args, {{.ObjectPrivateName}}.{{.GoName}})
 			{{end}}}
 		}
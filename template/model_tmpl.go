@@ -4,6 +4,8 @@ func (tp *TemplateParser) ParseModelTmpl() (string, error) {
 	return tp.execTmpl(`
 	type {{.Name}} struct {
 		{{range .Fields}} {{.GoName}} {{.GoType}} {{.GoTag}}
+		{{end}}{{range .BelongsTo}} {{.Name}} *{{.Related.Name}} {{$.Backtick}}db:"-"{{$.Backtick}}
+		{{end}}{{range .HasMany}} {{.Name}} {{.Related.Name}}List {{$.Backtick}}db:"-"{{$.Backtick}}
 		{{end}}
 	}
  
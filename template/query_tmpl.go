@@ -8,28 +8,53 @@ func (tp *TemplateParser) ParseRepoQueryImpl() (string, error) {
 		Filter{{.Name}}(filter Filter) Repository{{.Name}}Query
 		Pagination{{.Name}}(pagination Pagination) Repository{{.Name}}Query
 		OrderBy{{.Name}}(orderBy []Order) Repository{{.Name}}Query
-		Get{{.Name}}Count(ctx context.Context) (int, error)
+		Cursor{{.Name}}(cursor CursorPagination) Repository{{.Name}}Query
+		ForUpdate() Repository{{.Name}}Query
+		ForShare() Repository{{.Name}}Query
+		SkipLocked() Repository{{.Name}}Query
+		NoWait() Repository{{.Name}}Query
+		{{range .BelongsTo}}With{{.Name}}() Repository{{$.Name}}Query
+		{{end}}{{range .HasMany}}With{{.Name}}() Repository{{$.Name}}Query
+		{{end}}{{range .Relations}}{{if ne .Related.Table $.Table}}Join{{.Name}}() Repository{{$.Name}}{{.Name}}JoinQuery
+		{{end}}{{end}}Get{{.Name}}Count(ctx context.Context) (int, error)
 		Get{{.Name}}(ctx context.Context)  (*{{.ModelPackage}}{{.Name}}, error)
 		Get{{.Name}}List(ctx context.Context)  ({{.ModelPackage}}{{.Name}}List, error)
+		Get{{.Name}}CursorList(ctx context.Context) ({{.ModelPackage}}{{.Name}}List, string, error)
+		Get{{.Name}}Page(ctx context.Context) (*{{.Name}}Page, error)
+		Each{{.Name}}(ctx context.Context, fn func(*{{.ModelPackage}}{{.Name}}) error) error
+		GroupBy{{.Name}}(fields ...{{.Name}}Field) Repository{{.Name}}GroupQuery
+		{{range .Fields}}{{if .Numeric}}Sum{{.ObjectName}}{{.GoName}}(ctx context.Context) ({{.SumType}}, error)
+		Avg{{.ObjectName}}{{.GoName}}(ctx context.Context) (float64, error)
+		{{end}}{{if or .Numeric .Temporal}}Min{{.ObjectName}}{{.GoName}}(ctx context.Context) (*{{.BaseType}}, error)
+		Max{{.ObjectName}}{{.GoName}}(ctx context.Context) (*{{.BaseType}}, error)
+		{{end}}{{end}}		{{if .CompositeKey}}Get{{.Name}}ByKey(ctx context.Context, key {{.Name}}Key) (*{{.ModelPackage}}{{.Name}}, error){{end}}
+		{{range .Indexes}}{{if .Unique}}Get{{$.Name}}By{{.Name}}(ctx context.Context, {{range .Fields}}{{.PrivateName}} {{.GoType}}, {{end}}) (*{{$.ModelPackage}}{{$.Name}}, error)
+		{{else}}List{{$.Name}}By{{.Name}}(ctx context.Context, {{range .Fields}}{{.PrivateName}} {{.GoType}}, {{end}}) ({{$.ModelPackage}}{{$.Name}}List, error)
+		{{end}}{{end}}		{{range .BelongsTo}}Get{{$.Name}}{{.Name}}(ctx context.Context, {{$.PrivateName}} *{{$.ModelPackage}}{{$.Name}}) (*{{$.ModelPackage}}{{.Related.Name}}, error)
+		{{end}}{{range .HasMany}}Get{{$.Name}}{{.Name}}List(ctx context.Context, {{$.PrivateName}} *{{$.ModelPackage}}{{$.Name}}) ({{$.ModelPackage}}{{.Related.Name}}List, error)
+		{{end}}
 	}
 
 	type Repository{{.Name}}QueryImpl struct {
-		db   *sqlx.DB
+		db   DBTX
 		query string
 		filter      Filter
 		orderBy     []Order
 		pagination  Pagination
+		cursor      CursorPagination
 		fields      {{.Name}}FieldList
+		inTx        bool
+		lock        string
+		lockOption  string
+		{{range .BelongsTo}}with{{.Name}} bool
+		{{end}}{{range .HasMany}}with{{.Name}} bool
+		{{end}}
 	}
 
 	func (repo *Repository{{.Name}}QueryImpl) Select{{.Name}}(fields ...{{.Name}}Field) Repository{{.Name}}Query {
-		return &Repository{{.Name}}QueryImpl{
-			db:         repo.db,
-			filter:     repo.filter,
-			orderBy:    repo.orderBy,
-			pagination: repo.pagination,
-			fields:     fields,
-		}
+		cloned := repo.clone()
+		cloned.fields = fields
+		return cloned
 	}
 
 	func (repo *Repository{{.Name}}QueryImpl) Exclude{{.Name}}(excludedFields ...{{.Name}}Field) Repository{{.Name}}Query {
@@ -41,67 +66,160 @@ func (tp *TemplateParser) ParseRepoQueryImpl() (string, error) {
 			selectedFields = append(selectedFields, {{.Name}}Field(sel))
 		}
 
-		return &Repository{{.Name}}QueryImpl{
-			db:         repo.db,
-			filter:     repo.filter,
-			orderBy:    repo.orderBy,
-			pagination: repo.pagination,
-			fields:     selectedFields,
-		}
+		cloned := repo.clone()
+		cloned.fields = selectedFields
+		return cloned
 	}
 
 	func (repo *Repository{{.Name}}QueryImpl) Filter{{.Name}}(filter Filter) Repository{{.Name}}Query {
-		return &Repository{{.Name}}QueryImpl{
-			db:         repo.db,
-			filter:     filter,
-			orderBy:    repo.orderBy,
-			pagination: repo.pagination,
-			fields:     repo.fields,
-		}
+		cloned := repo.clone()
+		cloned.filter = filter
+		return cloned
 	}
 
 	func (repo *Repository{{.Name}}QueryImpl) Pagination{{.Name}}(pagination Pagination) Repository{{.Name}}Query {
-		return &Repository{{.Name}}QueryImpl{
-			db:         repo.db,
-			filter:     repo.filter,
-			orderBy:    repo.orderBy,
-			pagination: pagination,
-			fields:     repo.fields,
-		}
+		cloned := repo.clone()
+		cloned.pagination = pagination
+		return cloned
 	}
 
 	func (repo *Repository{{.Name}}QueryImpl) OrderBy{{.Name}}(orderBy []Order) Repository{{.Name}}Query {
-		return &Repository{{.Name}}QueryImpl{
-			db:         repo.db,
-			filter:     repo.filter,
-			orderBy:    orderBy,
-			pagination: repo.pagination,
-			fields:     repo.fields,
-		}
+		cloned := repo.clone()
+		cloned.orderBy = orderBy
+		return cloned
+	}
+
+	func (repo *Repository{{.Name}}QueryImpl) Cursor{{.Name}}(cursor CursorPagination) Repository{{.Name}}Query {
+		cloned := repo.clone()
+		cloned.cursor = cursor
+		return cloned
+	}
+
+	// ForUpdate locks the selected rows until the end of the transaction, the
+	// repository must be bound to a transaction.
+	func (repo *Repository{{.Name}}QueryImpl) ForUpdate() Repository{{.Name}}Query {
+		cloned := repo.clone()
+		cloned.lock = "UPDATE"
+		return cloned
+	}
+
+	// ForShare locks the selected rows against the updates until the end of
+	// the transaction, the repository must be bound to a transaction.
+	func (repo *Repository{{.Name}}QueryImpl) ForShare() Repository{{.Name}}Query {
+		cloned := repo.clone()
+		cloned.lock = "SHARE"
+		return cloned
+	}
+
+	// SkipLocked skips the rows locked by other transactions instead of
+	// waiting for them.
+	func (repo *Repository{{.Name}}QueryImpl) SkipLocked() Repository{{.Name}}Query {
+		cloned := repo.clone()
+		cloned.lockOption = "SKIP LOCKED"
+		return cloned
+	}
+
+	// NoWait fails instead of waiting for the rows locked by other
+	// transactions.
+	func (repo *Repository{{.Name}}QueryImpl) NoWait() Repository{{.Name}}Query {
+		cloned := repo.clone()
+		cloned.lockOption = "NOWAIT"
+		return cloned
+	}
+
+	{{range .BelongsTo}}func (repo *Repository{{$.Name}}QueryImpl) With{{.Name}}() Repository{{$.Name}}Query {
+		cloned := repo.clone()
+		cloned.with{{.Name}} = true
+		return cloned
+	}
+
+	{{end}}{{range .HasMany}}func (repo *Repository{{$.Name}}QueryImpl) With{{.Name}}() Repository{{$.Name}}Query {
+		cloned := repo.clone()
+		cloned.with{{.Name}} = true
+		return cloned
+	}
+
+	{{end}}func (repo *Repository{{.Name}}QueryImpl) clone() *Repository{{.Name}}QueryImpl {
+		cloned := *repo
+		return &cloned
 	}
 
 	func (repo *Repository{{.Name}}QueryImpl) Get{{.Name}}List(ctx context.Context)  ({{.ModelPackage}}{{.Name}}List, error) {
-		var (
-			{{.PrivateName}}List {{.ModelPackage}}{{.Name}}List
-			values []interface{}
-		)
+		query, values, err := repo.selectQuery()
+		if err != nil {
+			return nil, err
+		}
 
-		if len(repo.fields) == 0 {
-			repo.fields = {{.Name}}SelectFields{}.All()
+		var {{.PrivateName}}List {{.ModelPackage}}{{.Name}}List
+		err = repo.db.SelectContext(ctx, &{{.PrivateName}}List, rebind(query), values...)
+		if err != nil {
+			return nil, err
 		}
+		{{range .BelongsTo}}
+		if repo.with{{.Name}} {
+			if err := repo.load{{$.Name}}{{.Name}}(ctx, {{$.PrivateName}}List); err != nil {
+				return nil, err
+			}
+		}{{end}}{{range .HasMany}}
+		if repo.with{{.Name}} {
+			if err := repo.load{{$.Name}}{{.Name}}(ctx, {{$.PrivateName}}List); err != nil {
+				return nil, err
+			}
+		}{{end}}
+		return {{.PrivateName}}List, nil
+	}
 
-		query := fmt.Sprintf("SELECT %s FROM {{.Backtick}}{{.Table}}{{.Backtick}}", strings.Join(repo.fields.toString(), ","))
+	// Each{{.Name}} calls fn with the rows one by one as they are read, instead
+	// of loading them all, and stops at the first error returned by fn. The
+	// relations are not loaded.
+	func (repo *Repository{{.Name}}QueryImpl) Each{{.Name}}(ctx context.Context, fn func(*{{.ModelPackage}}{{.Name}}) error) error {
+		query, values, err := repo.selectQuery()
+		if err != nil {
+			return err
+		}
+
+		rows, err := repo.db.QueryxContext(ctx, rebind(query), values...)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			var {{.PrivateName}} {{.ModelPackage}}{{.Name}}
+			if err := rows.StructScan(&{{.PrivateName}}); err != nil {
+				return err
+			}
+			if err := fn(&{{.PrivateName}}); err != nil {
+				return err
+			}
+		}
+		return rows.Err()
+	}
+
+	func (repo *Repository{{.Name}}QueryImpl) selectQuery() (string, []interface{}, error) {
+		var values []interface{}
+		fields := repo.fields
+		if len(fields) == 0 {
+			fields = {{.Name}}SelectFields{}.All()
+		}
+
+		query := fmt.Sprintf("SELECT %s FROM {{.QuotedTable}}", strings.Join(fields.quoted(), ","))
 		if repo.filter != nil {
-			query += " WHERE "+repo.filter.Query()
-			values = append(values, repo.filter.Values()...)
+			if err := filterErr(repo.filter); err != nil {
+				return "", nil, err
+			}
+			if filterQuery := repo.filter.Query(); filterQuery != "" {
+				query += " WHERE " + filterQuery
+				values = append(values, repo.filter.Values()...)
+			}
 		}
 
 		if len(repo.orderBy) > 0 {
-			var orderStr []string
-			for _, order := range repo.orderBy {
-				orderStr = append(orderStr, order.Value()+" "+order.Direction())
+			orderBy, err := orderByClause(repo.orderBy)
+			if err != nil {
+				return "", nil, err
 			}
-			query += fmt.Sprintf(" ORDER BY %s", strings.Join(orderStr, ","))
+			query += " ORDER BY " + orderBy
 		}
 
 		if repo.pagination != nil {
@@ -109,23 +227,33 @@ func (tp *TemplateParser) ParseRepoQueryImpl() (string, error) {
 			query += fmt.Sprintf(" LIMIT %d OFFSET %d", repo.pagination.GetSize(), offset)
 		}
 
-		err := repo.db.SelectContext(ctx, &{{.PrivateName}}List, query, values...)
-		if err != nil {
-			return nil, err
+		if repo.lock != "" || repo.lockOption != "" {
+			if !repo.inTx {
+				return "", nil, errors.New("{{.LowerName}} row locks need a repository bound to a transaction")
+			}
+			if repo.lock == "" {
+				return "", nil, fmt.Errorf("{{.LowerName}} %s needs ForUpdate or ForShare", repo.lockOption)
+			}
+			query += lockClause(repo.lock, repo.lockOption)
 		}
-		return {{.PrivateName}}List, nil
+		return query, values, nil
 	}
 
 	func (repo *Repository{{.Name}}QueryImpl) Get{{.Name}}Count(ctx context.Context) (int, error) {
 		var values []interface{}
-		query := fmt.Sprintf("SELECT count(1) FROM {{.Backtick}}{{.Table}}{{.Backtick}}")
+		query := fmt.Sprintf("SELECT count(1) FROM {{.QuotedTable}}")
 		if repo.filter != nil {
-			query += " WHERE "+repo.filter.Query()
-			values = append(values, repo.filter.Values()...)
+			if err := filterErr(repo.filter); err != nil {
+				return 0, err
+			}
+			if filterQuery := repo.filter.Query(); filterQuery != "" {
+				query += " WHERE " + filterQuery
+				values = append(values, repo.filter.Values()...)
+			}
 		}
 
 		var count int
-		err := repo.db.QueryRowContext(ctx, query, values...).Scan(&count)
+		err := repo.db.QueryRowContext(ctx, rebind(query), values...).Scan(&count)
 		return count, err
 	}
 
@@ -142,14 +270,182 @@ func (tp *TemplateParser) ParseRepoQueryImpl() (string, error) {
 		return {{.PrivateName}}List[0], nil
 	}
 
-	func NewRepo{{.Name}}Query(db *sqlx.DB) Repository{{.Name}}Query {
+	// {{.Name}}Page is a page of {{.Table}} along with the count of the rows
+	// matching the filter.
+	type {{.Name}}Page struct {
+		Items      {{.ModelPackage}}{{.Name}}List
+		Total      int
+		Page       int
+		Size       int
+		TotalPages int
+	}
+
+	// Get{{.Name}}Page returns the page of the pagination, or every row
+	// without pagination, and the count of the rows matching the filter.
+	func (repo *Repository{{.Name}}QueryImpl) Get{{.Name}}Page(ctx context.Context) (*{{.Name}}Page, error) {
+		total, err := repo.Get{{.Name}}Count(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		{{.PrivateName}}List, err := repo.Get{{.Name}}List(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		page := &{{.Name}}Page{
+			Items: {{.PrivateName}}List,
+			Total: total,
+			Page:  1,
+			Size:  total,
+		}
+		if repo.pagination != nil {
+			page.Page = repo.pagination.GetPage()
+			page.Size = repo.pagination.GetSize()
+		}
+		if page.Size > 0 {
+			page.TotalPages = (total + page.Size - 1) / page.Size
+		}
+		return page, nil
+	}
+
+	{{if .CompositeKey}}func (repo *Repository{{.Name}}QueryImpl) Get{{.Name}}ByKey(ctx context.Context, key {{.Name}}Key) (*{{.ModelPackage}}{{.Name}}, error) {
+		filter := New{{.Name}}Filter("AND"){{range .PrimaryKeys}}.
+			SetFilterBy{{.GoName}}(key.{{.GoName}}, "="){{end}}
+		return repo.Filter{{.Name}}(filter).Get{{.Name}}(ctx)
+	}
+
+	type {{.Name}}Key struct {
+		{{range .PrimaryKeys}} {{.GoName}} {{.GoType}}
+		{{end}}
+	}
+	{{end}}
+
+	{{range .Indexes}}{{if .Unique}}func (repo *Repository{{$.Name}}QueryImpl) Get{{$.Name}}By{{.Name}}(ctx context.Context, {{range .Fields}}{{.PrivateName}} {{.GoType}}, {{end}}) (*{{$.ModelPackage}}{{$.Name}}, error) {
+		filter := New{{$.Name}}Filter("AND"){{range .Fields}}.
+			SetFilterBy{{.GoName}}({{.PrivateName}}, "="){{end}}
+		return repo.Filter{{$.Name}}(filter).Get{{$.Name}}(ctx)
+	}
+
+	{{else}}func (repo *Repository{{$.Name}}QueryImpl) List{{$.Name}}By{{.Name}}(ctx context.Context, {{range .Fields}}{{.PrivateName}} {{.GoType}}, {{end}}) ({{$.ModelPackage}}{{$.Name}}List, error) {
+		filter := New{{$.Name}}Filter("AND"){{range .Fields}}.
+			SetFilterBy{{.GoName}}({{.PrivateName}}, "="){{end}}
+		return repo.Filter{{$.Name}}(filter).Get{{$.Name}}List(ctx)
+	}
+
+	{{end}}{{end}}{{range .BelongsTo}}func (repo *Repository{{$.Name}}QueryImpl) Get{{$.Name}}{{.Name}}(ctx context.Context, {{$.PrivateName}} *{{$.ModelPackage}}{{$.Name}}) (*{{$.ModelPackage}}{{.Related.Name}}, error) {
+		{{if and .Field.Nullable .Field.GoNullTypeSel}}if !{{$.PrivateName}}.{{.Field.GoName}}.Valid {
+			return nil, nil
+		}
+		{{end}}filter := New{{.Related.Name}}Filter("AND").SetFilterBy{{.RelatedField.GoName}}({{$.PrivateName}}.{{.Field.GoName}}, "=")
+		return (&Repository{{.Related.Name}}QueryImpl{db: repo.db}).Filter{{.Related.Name}}(filter).Get{{.Related.Name}}(ctx)
+	}
+
+	{{end}}{{range .HasMany}}func (repo *Repository{{$.Name}}QueryImpl) Get{{$.Name}}{{.Name}}List(ctx context.Context, {{$.PrivateName}} *{{$.ModelPackage}}{{$.Name}}) ({{$.ModelPackage}}{{.Related.Name}}List, error) {
+		{{if and .Field.Nullable .Field.GoNullTypeSel}}if !{{$.PrivateName}}.{{.Field.GoName}}.Valid {
+			return nil, nil
+		}
+		{{end}}filter := New{{.Related.Name}}Filter("AND").SetFilterBy{{.RelatedField.GoName}}({{$.PrivateName}}.{{.Field.GoName}}, "=")
+		return (&Repository{{.Related.Name}}QueryImpl{db: repo.db}).Filter{{.Related.Name}}(filter).Get{{.Related.Name}}List(ctx)
+	}
+
+	{{end}}{{range .BelongsTo}}func (repo *Repository{{$.Name}}QueryImpl) load{{$.Name}}{{.Name}}(ctx context.Context, {{$.PrivateName}}List {{$.ModelPackage}}{{$.Name}}List) error {
+		var keys []interface{}
+		loadedKeys := make(map[interface{}]bool)
+		for _, {{$.PrivateName}} := range {{$.PrivateName}}List {
+			{{if and .Field.Nullable .Field.GoNullTypeSel}}if !{{$.PrivateName}}.{{.Field.GoName}}.Valid {
+				continue
+			}
+			{{end}}key := relationKey({{$.PrivateName}}.{{.Field.GoName}})
+			if !loadedKeys[key] {
+				loadedKeys[key] = true
+				keys = append(keys, {{$.PrivateName}}.{{.Field.GoName}})
+			}
+		}
+		if len(keys) == 0 {
+			return nil
+		}
+
+		var relatedList {{$.ModelPackage}}{{.Related.Name}}List
+		for _, batch := range relationKeyBatches(keys) {
+			filter := New{{.Related.Name}}Filter("AND").SetFilterBy{{.RelatedField.GoName}}(batch, "IN")
+			batchList, err := (&Repository{{.Related.Name}}QueryImpl{db: repo.db}).Filter{{.Related.Name}}(filter).Get{{.Related.Name}}List(ctx)
+			if err != nil {
+				return err
+			}
+			relatedList = append(relatedList, batchList...)
+		}
+
+		related := make(map[interface{}]*{{$.ModelPackage}}{{.Related.Name}})
+		for _, item := range relatedList {
+			related[relationKey(item.{{.RelatedField.GoName}})] = item
+		}
+		for _, {{$.PrivateName}} := range {{$.PrivateName}}List {
+			{{$.PrivateName}}.{{.Name}} = related[relationKey({{$.PrivateName}}.{{.Field.GoName}})]
+		}
+		return nil
+	}
+
+	{{end}}{{range .HasMany}}func (repo *Repository{{$.Name}}QueryImpl) load{{$.Name}}{{.Name}}(ctx context.Context, {{$.PrivateName}}List {{$.ModelPackage}}{{$.Name}}List) error {
+		var keys []interface{}
+		loadedKeys := make(map[interface{}]bool)
+		for _, {{$.PrivateName}} := range {{$.PrivateName}}List {
+			{{if and .Field.Nullable .Field.GoNullTypeSel}}if !{{$.PrivateName}}.{{.Field.GoName}}.Valid {
+				continue
+			}
+			{{end}}key := relationKey({{$.PrivateName}}.{{.Field.GoName}})
+			if !loadedKeys[key] {
+				loadedKeys[key] = true
+				keys = append(keys, {{$.PrivateName}}.{{.Field.GoName}})
+			}
+		}
+		if len(keys) == 0 {
+			return nil
+		}
+
+		var relatedList {{$.ModelPackage}}{{.Related.Name}}List
+		for _, batch := range relationKeyBatches(keys) {
+			filter := New{{.Related.Name}}Filter("AND").SetFilterBy{{.RelatedField.GoName}}(batch, "IN")
+			batchList, err := (&Repository{{.Related.Name}}QueryImpl{db: repo.db}).Filter{{.Related.Name}}(filter).Get{{.Related.Name}}List(ctx)
+			if err != nil {
+				return err
+			}
+			relatedList = append(relatedList, batchList...)
+		}
+
+		related := make(map[interface{}]{{$.ModelPackage}}{{.Related.Name}}List)
+		for _, item := range relatedList {
+			key := relationKey(item.{{.RelatedField.GoName}})
+			related[key] = append(related[key], item)
+		}
+		for _, {{$.PrivateName}} := range {{$.PrivateName}}List {
+			{{$.PrivateName}}.{{.Name}} = related[relationKey({{$.PrivateName}}.{{.Field.GoName}})]
+		}
+		return nil
+	}
+
+	{{end}}func NewRepo{{.Name}}Query(db DBTX) Repository{{.Name}}Query {
+		_, inTx := db.(*sqlx.Tx)
 		return &Repository{{.Name}}QueryImpl{
-			db: db,
+			db:   db,
+			inTx: inTx,
+		}
+	}
+
+	// NewRepo{{.Name}}QueryFromTx creates a query repository reading within the
+	// transaction, which sees the writes of the transaction.
+	func NewRepo{{.Name}}QueryFromTx(tx DBTX) Repository{{.Name}}Query {
+		return &Repository{{.Name}}QueryImpl{
+			db:   tx,
+			inTx: true,
 		}
 	}
 	` + templateFields +
 		templateFilter +
-		templateOrder)
+		templateOrder +
+		templateCursor +
+		templateAggregate +
+		templateJoin)
 }
 
 func (tp *TemplateParser) ParseRepositoryArgs() (string, error) {
@@ -169,6 +465,28 @@ func (tp *TemplateParser) ParseRepositoryArgs() (string, error) {
 			Direction() string
 		}
 
+		const (
+			OrderAsc  = "ASC"
+			OrderDesc = "DESC"
+		)
+
+		// CursorPagination paginates with the Cursor returned along the previous
+		// page, empty for the first page, instead of an offset.
+		type CursorPagination struct {
+			Cursor string
+			Size   int
+		}
+
+		// DBTX runs the statements of the repositories, it is implemented by
+		// *sqlx.DB, *sqlx.Tx and *sqlx.Conn.
+		type DBTX interface {
+			PreparexContext(ctx context.Context, query string) (*sqlx.Stmt, error)
+			QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+			QueryxContext(ctx context.Context, query string, args ...interface{}) (*sqlx.Rows, error)
+			QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+			SelectContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error
+		}
+
 		type PaginationData struct {
 			Page int
 			Size int
@@ -185,11 +503,330 @@ func (tp *TemplateParser) ParseRepositoryArgs() (string, error) {
 		type InsertResult struct {
 			sql.Result
 		}
+
+		type filterCondition struct {
+			column string
+			query  string
+		}
+
+		type filterGroup struct {
+			operator string
+			filters  []Filter
+		}
+
+		func And(filters ...Filter) Filter {
+			return filterGroup{
+				operator: "AND",
+				filters:  filters,
+			}
+		}
+
+		func Or(filters ...Filter) Filter {
+			return filterGroup{
+				operator: "OR",
+				filters:  filters,
+			}
+		}
+
+		func (g filterGroup) Query() string {
+			var query []string
+			for _, filter := range g.filters {
+				if filterQuery := filter.Query(); filterQuery != "" {
+					query = append(query, "("+filterQuery+")")
+				}
+			}
+			return strings.Join(query, " "+g.operator+" ")
+		}
+
+		func (g filterGroup) Values() []interface{} {
+			var values []interface{}
+			for _, filter := range g.filters {
+				values = append(values, filter.Values()...)
+			}
+			return values
+		}
+
+		type notFilter struct {
+			filter Filter
+		}
+
+		func Not(filter Filter) Filter {
+			return notFilter{
+				filter: filter,
+			}
+		}
+
+		func (n notFilter) Query() string {
+			query := n.filter.Query()
+			if query == "" {
+				return ""
+			}
+			return "NOT (" + query + ")"
+		}
+
+		func (n notFilter) Values() []interface{} {
+			return n.filter.Values()
+		}
+
+		func (g filterGroup) Err() error {
+			for _, filter := range g.filters {
+				if err := filterErr(filter); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+
+		func (n notFilter) Err() error {
+			return filterErr(n.filter)
+		}
 		`)
 }
 
 func (tp *TemplateParser) ParseInternalFunc() (string, error) {
 	return tp.execTmpl(`
+	// filterOperators are the comparison operators accepted by the SetFilterBy
+	// methods of the filters.
+	var filterOperators = map[string]bool{
+		"=":           true,
+		"!=":          true,
+		"{{.LessThan}}>":          true,
+		"{{.LessThan}}":           true,
+		"{{.LessThan}}=":          true,
+		">":           true,
+		">=":          true,
+		"LIKE":        true,
+		"NOT LIKE":    true,
+		"ILIKE":       true,
+		"NOT ILIKE":   true,
+		"IN":          true,
+		"NOT IN":      true,
+		"IS":          true,
+		"IS NOT":      true,
+		"IS NULL":     true,
+		"IS NOT NULL": true,
+	}
+
+	// filterOperator normalizes the comparison operator, an unknown operator
+	// is an error so that no arbitrary SQL ends up in the query.
+	func filterOperator(operator string) (string, error) {
+		normalized := strings.ToUpper(strings.Join(strings.Fields(operator), " "))
+		if !filterOperators[normalized] {
+			return "", fmt.Errorf("unknown filter operator %q", operator)
+		}
+		return normalized, nil
+	}
+
+	// filterErr returns the error of the filter when it reports one, like the
+	// generated filters do.
+	func filterErr(filter Filter) error {
+		if filter, ok := filter.(interface{ Err() error }); ok {
+			return filter.Err()
+		}
+		return nil
+	}
+
+	// isEmptySlice tells whether the value of an IN condition has no element,
+	// which sqlx.In could not expand.
+	func isEmptySlice(value interface{}) bool {
+		v := reflect.ValueOf(value)
+		if v.Kind() != reflect.Slice && v.Kind() != reflect.Array {
+			return false
+		}
+		return v.Len() == 0 && v.Type().Elem().Kind() != reflect.Uint8
+	}
+
+	// parseOrderDirection validates the direction of an order, ASC, DESC or
+	// empty, optionally followed by NULLS FIRST or NULLS LAST.
+	func parseOrderDirection(direction string) (string, string, error) {
+		words := strings.Fields(strings.ToUpper(direction))
+		var order, nulls string
+		if len(words) > 0 && (words[0] == OrderAsc || words[0] == OrderDesc) {
+			order, words = words[0], words[1:]
+		}
+		if len(words) == 2 && words[0] == "NULLS" && (words[1] == "FIRST" || words[1] == "LAST") {
+			nulls, words = words[1], nil
+		}
+		if len(words) > 0 {
+			return "", "", fmt.Errorf("invalid order direction %q", direction)
+		}
+		return order, nulls, nil
+	}
+
+	func orderByClause(orders []Order) (string, error) {
+		var clauses []string
+		for _, order := range orders {
+			direction, nulls, err := parseOrderDirection(order.Direction())
+			if err != nil {
+				return "", err
+			}
+
+			column := order.Value()
+			{{if eq .Dialect "mysql"}}// MySQL has no NULLS FIRST and NULLS LAST, the nulls are sorted by
+			// the IS NULL flag of the column first.
+			switch nulls {
+			case "FIRST":
+				clauses = append(clauses, column+" IS NULL DESC")
+			case "LAST":
+				clauses = append(clauses, column+" IS NULL ASC")
+			}
+			clauses = append(clauses, strings.TrimSpace(column+" "+direction)){{else}}clause := strings.TrimSpace(column + " " + direction)
+			if nulls != "" {
+				clause += " NULLS " + nulls
+			}
+			clauses = append(clauses, clause){{end}}
+		}
+		return strings.Join(clauses, ","), nil
+	}
+
+	func hasOrder(orders []Order, order Order) bool {
+		for _, listed := range orders {
+			if listed.Value() == order.Value() {
+				return true
+			}
+		}
+		return false
+	}
+
+	// cursorFilter compares the columns with the values of a cursor as a row,
+	// e.g. (created_at, id) > (?, ?).
+	type cursorFilter struct {
+		columns  []string
+		operator string
+		values   []interface{}
+	}
+
+	func (c cursorFilter) Query() string {
+		return "(" + strings.Join(c.columns, ", ") + ") " + c.operator + " (" + placeholders(len(c.values)) + ")"
+	}
+
+	func (c cursorFilter) Values() []interface{} {
+		return c.values
+	}
+
+	func encodeCursor(values []interface{}) (string, error) {
+		cursor, err := json.Marshal(values)
+		if err != nil {
+			return "", err
+		}
+		return base64.RawURLEncoding.EncodeToString(cursor), nil
+	}
+
+	func decodeCursor(cursor string, n int) ([]json.RawMessage, error) {
+		decoded, err := base64.RawURLEncoding.DecodeString(cursor)
+		if err != nil {
+			return nil, errors.New("invalid cursor")
+		}
+		var values []json.RawMessage
+		if err := json.Unmarshal(decoded, &values); err != nil || len(values) != n {
+			return nil, errors.New("invalid cursor")
+		}
+		return values, nil
+	}
+
+	// timeScanner scans a nullable time, which is returned as text by SQLite
+	// for the aggregates or by MySQL without parseTime.
+	type timeScanner struct {
+		time **time.Time
+	}
+
+	var timeScannerLayouts = []string{
+		"2006-01-02 15:04:05.999999999-07:00",
+		"2006-01-02T15:04:05.999999999-07:00",
+		"2006-01-02 15:04:05.999999999",
+		"2006-01-02T15:04:05.999999999",
+		"2006-01-02 15:04",
+		"2006-01-02T15:04",
+		"2006-01-02",
+	}
+
+	func (s timeScanner) Scan(value interface{}) error {
+		var text string
+		switch value := value.(type) {
+		case nil:
+			*s.time = nil
+			return nil
+		case time.Time:
+			*s.time = &value
+			return nil
+		case []byte:
+			text = string(value)
+		case string:
+			text = value
+		default:
+			return fmt.Errorf("unsupported time value %T", value)
+		}
+
+		text = strings.TrimSuffix(text, "Z")
+		for _, layout := range timeScannerLayouts {
+			if parsed, err := time.ParseInLocation(layout, text, time.UTC); err == nil {
+				*s.time = &parsed
+				return nil
+			}
+		}
+		return fmt.Errorf("invalid time value %q", text)
+	}
+
+	{{if eq .Dialect "sqlite3"}}// lockClause returns no clause since SQLite has no row locks, a write
+	// transaction locks the whole database.
+	func lockClause(lock, option string) string {
+		return ""
+	}{{else}}// lockClause returns the locking clause of the selected rows{{if eq .Dialect "mysql"}}, the shared
+	// locks without option use LOCK IN SHARE MODE which MySQL 5.7 supports{{end}}.
+	func lockClause(lock, option string) string {
+		{{if eq .Dialect "mysql"}}if lock == "SHARE" && option == "" {
+			return " LOCK IN SHARE MODE"
+		}
+		{{end}}clause := " FOR " + lock
+		if option != "" {
+			clause += " " + option
+		}
+		return clause
+	}{{end}}
+
+	func placeholders(n int) string {
+		return strings.TrimSuffix(strings.Repeat("?, ", n), ", ")
+	}
+
+	func rebind(query string) string {
+		return sqlx.Rebind(sqlx.{{.BindType}}, query)
+	}
+
+	// relationKey returns the comparable value of a relation column, so the
+	// related rows could be matched whatever the Go types of both columns are.
+	func relationKey(value interface{}) interface{} {
+		key, err := driver.DefaultParameterConverter.ConvertValue(value)
+		if err != nil {
+			return value
+		}
+		if bytes, ok := key.([]byte); ok {
+			return string(bytes)
+		}
+		return key
+	}
+
+	// relationBatchSize is the maximum number of keys of the IN list loading the
+	// related rows, which keeps the queries under the placeholders limit of the
+	// databases, e.g. 65535 on PostgreSQL.
+	const relationBatchSize = 1000
+
+	// relationKeyBatches splits the keys into batches of relationBatchSize keys.
+	func relationKeyBatches(keys []interface{}) [][]interface{} {
+		var batches [][]interface{}
+		for len(keys) > relationBatchSize {
+			batches = append(batches, keys[:relationBatchSize])
+			keys = keys[relationBatchSize:]
+		}
+		return append(batches, keys)
+	}
+
+	// quoteIdentifier quotes a table or column name, doubling the quotes it
+	// contains.
+	func quoteIdentifier(identifier string) string {
+		quote := {{.IdentifierQuote}}
+		return quote + strings.ReplaceAll(identifier, quote, quote+quote) + quote
+	}
+
 	func excludeFields(excludedFields, allFields []string) []string {
 		var selectedFields []string
 			for _, field := range allFields {
@@ -223,6 +860,14 @@ var (
 		return fieldsStr
 	}
 
+	func (fieldList {{.Name}}FieldList) quoted() []string {
+		var fieldsStr []string
+		for _, field := range fieldList {
+			fieldsStr = append(fieldsStr, quoteIdentifier(string(field)))
+		}
+		return fieldsStr
+	}
+
 
 	type {{.Name}}SelectFields struct {	
 	}
@@ -241,13 +886,18 @@ var (
 	}
 	`
 	templateFilter = `type {{.Name}}Filter struct {
-		operator string
-		query  []string
-		values []interface{}
+		operator   string
+		qualified  bool
+		conditions []filterCondition
+		values     []interface{}
+		err        error
 	}
 
+	// New{{.Name}}Filter creates a filter joining its conditions with the
+	// operator, OR or AND by default.
 	func New{{.Name}}Filter(operator string) {{.Name}}Filter {
-		if operator == "" {
+		operator = strings.ToUpper(strings.TrimSpace(operator))
+		if operator != "OR" {
 			operator = "AND"
 		}
 		return {{.Name}}Filter{
@@ -256,28 +906,149 @@ var (
 	}
 
 	{{range .Fields}} func(f {{.ObjectName}}Filter) SetFilterBy{{.GoName}}(value interface{}, operator string) {{.ObjectName}}Filter {
-		query := "{{.DBField}} " + operator + " (?)"
-		var values []interface{}
+		operator, err := filterOperator(operator)
+		if err != nil {
+			return f.fail(err)
+		}
 		if value == nil {
-			query = "{{.DBField}} " + operator
-		} else {
-			switch strings.ToUpper(operator) {
-			case "IN", "NOT IN":
-				query, values, _ = sqlx.In(query, value)
-			default:
-				values = append(values, value)
+			return f.where("{{.DBField}}", operator)
+		}
+
+		switch operator {
+		case "IN", "NOT IN":
+			if isEmptySlice(value) {
+				if operator == "IN" {
+					return f.where("", "1 = 0")
+				}
+				return f.where("", "1 = 1")
+			}
+			query, values, err := sqlx.In(operator+" (?)", value)
+			if err != nil {
+				return f.fail(err)
 			}
+			return f.where("{{.DBField}}", query, values...)
 		}
-		return {{.ObjectName}}Filter {
-			operator: f.operator,
-			query:  append(f.query, query),
-			values: append(f.values, values...),
+		return f.where("{{.DBField}}", operator+" (?)", value)
+	}
+
+	func (f {{.ObjectName}}Filter) {{.GoName}}Eq(value {{.BaseType}}) {{.ObjectName}}Filter {
+		return f.where("{{.DBField}}", "= ?", value)
+	}
+
+	func (f {{.ObjectName}}Filter) {{.GoName}}Ne(value {{.BaseType}}) {{.ObjectName}}Filter {
+		return f.where("{{.DBField}}", "{{$.LessThan}}> ?", value)
+	}
+
+	func (f {{.ObjectName}}Filter) {{.GoName}}In(values []{{.BaseType}}) {{.ObjectName}}Filter {
+		if len(values) == 0 {
+			return f.where("", "1 = 0")
+		}
+		args := make([]interface{}, len(values))
+		for i := range values {
+			args[i] = values[i]
+		}
+		return f.where("{{.DBField}}", "IN ("+placeholders(len(args))+")", args...)
+	}
+
+	func (f {{.ObjectName}}Filter) {{.GoName}}NotIn(values []{{.BaseType}}) {{.ObjectName}}Filter {
+		if len(values) == 0 {
+			return f.where("", "1 = 1")
 		}
+		args := make([]interface{}, len(values))
+		for i := range values {
+			args[i] = values[i]
+		}
+		return f.where("{{.DBField}}", "NOT IN ("+placeholders(len(args))+")", args...)
+	}
+	{{if or .Numeric .Temporal .Text}}
+	func (f {{.ObjectName}}Filter) {{.GoName}}Gt(value {{.BaseType}}) {{.ObjectName}}Filter {
+		return f.where("{{.DBField}}", "> ?", value)
+	}
+
+	func (f {{.ObjectName}}Filter) {{.GoName}}Gte(value {{.BaseType}}) {{.ObjectName}}Filter {
+		return f.where("{{.DBField}}", ">= ?", value)
+	}
+
+	func (f {{.ObjectName}}Filter) {{.GoName}}Lt(value {{.BaseType}}) {{.ObjectName}}Filter {
+		return f.where("{{.DBField}}", "{{$.LessThan}} ?", value)
+	}
+
+	func (f {{.ObjectName}}Filter) {{.GoName}}Lte(value {{.BaseType}}) {{.ObjectName}}Filter {
+		return f.where("{{.DBField}}", "{{$.LessThan}}= ?", value)
+	}
+
+	func (f {{.ObjectName}}Filter) {{.GoName}}Between(from, to {{.BaseType}}) {{.ObjectName}}Filter {
+		return f.where("{{.DBField}}", "BETWEEN ? AND ?", from, to)
+	}
+	{{end}}{{if .Text}}
+	func (f {{.ObjectName}}Filter) {{.GoName}}Like(pattern string) {{.ObjectName}}Filter {
+		return f.where("{{.DBField}}", "LIKE ?", pattern)
 	}
+
+	func (f {{.ObjectName}}Filter) {{.GoName}}NotLike(pattern string) {{.ObjectName}}Filter {
+		return f.where("{{.DBField}}", "NOT LIKE ?", pattern)
+	}
+	{{end}}{{if .Nullable}}
+	func (f {{.ObjectName}}Filter) {{.GoName}}IsNull() {{.ObjectName}}Filter {
+		return f.where("{{.DBField}}", "IS NULL")
+	}
+
+	func (f {{.ObjectName}}Filter) {{.GoName}}IsNotNull() {{.ObjectName}}Filter {
+		return f.where("{{.DBField}}", "IS NOT NULL")
+	}
+	{{end}}
 	{{end}}
 
+	// where returns a copy of the filter with the condition on the column, the
+	// condition is used as is when the column is empty.
+	func (f {{.Name}}Filter) where(column, query string, values ...interface{}) {{.Name}}Filter {
+		conditions := make([]filterCondition, len(f.conditions), len(f.conditions)+1)
+		copy(conditions, f.conditions)
+		return {{.Name}}Filter{
+			operator:   f.operator,
+			qualified:  f.qualified,
+			conditions: append(conditions, filterCondition{column: column, query: query}),
+			values:     append(append([]interface{}{}, f.values...), values...),
+			err:        f.err,
+		}
+	}
+
+	// fail returns a copy of the filter holding the error, which the
+	// repositories return instead of running the query. The filter matches no
+	// row meanwhile.
+	func (f {{.Name}}Filter) fail(err error) {{.Name}}Filter {
+		failed := f.where("", "1 = 0")
+		if failed.err == nil {
+			failed.err = err
+		}
+		return failed
+	}
+
+	// Err returns the error of the filter, e.g. an unknown operator given to a
+	// SetFilterBy method.
+	func (f {{.Name}}Filter) Err() error {
+		return f.err
+	}
+
+	func (f {{.Name}}Filter) Qualified() {{.Name}}Filter {
+		f.qualified = true
+		return f
+	}
+
 	func (f {{.Name}}Filter) Query() string {
-		return strings.Join(f.query, " "+f.operator+" ")
+		var query []string
+		for _, condition := range f.conditions {
+			if condition.column == "" {
+				query = append(query, condition.query)
+				continue
+			}
+			column := quoteIdentifier(condition.column)
+			if f.qualified {
+				column = "{{.QuotedTable}}." + column
+			}
+			query = append(query, column+" "+condition.query)
+		}
+		return strings.Join(query, " "+f.operator+" ")
 	}
 
 	func (f {{.Name}}Filter) Values() []interface{} {
@@ -288,21 +1059,540 @@ var (
 	templateOrder = `
 	{{range .Fields}}type {{.ObjectName}}{{.GoName}}Order struct {
 		direction string
+		nulls     string
+		qualified bool
 	}
 	func(o {{.ObjectName}}{{.GoName}}Order)SetDirection(direction string) {{.ObjectName}}{{.GoName}}Order {
-		return {{.ObjectName}}{{.GoName}}Order {
-			direction: direction,
-		}
+		o.direction = direction
+		return o
+	}
+	func(o {{.ObjectName}}{{.GoName}}Order)Asc() {{.ObjectName}}{{.GoName}}Order {
+		o.direction = OrderAsc
+		return o
+	}
+	func(o {{.ObjectName}}{{.GoName}}Order)Desc() {{.ObjectName}}{{.GoName}}Order {
+		o.direction = OrderDesc
+		return o
+	}
+	func(o {{.ObjectName}}{{.GoName}}Order)NullsFirst() {{.ObjectName}}{{.GoName}}Order {
+		o.nulls = "FIRST"
+		return o
+	}
+	func(o {{.ObjectName}}{{.GoName}}Order)NullsLast() {{.ObjectName}}{{.GoName}}Order {
+		o.nulls = "LAST"
+		return o
+	}
+	func(o {{.ObjectName}}{{.GoName}}Order)Qualified() {{.ObjectName}}{{.GoName}}Order {
+		o.qualified = true
+		return o
 	}
 	func(o {{.ObjectName}}{{.GoName}}Order)Value() string {
-		return "{{.DBField}}"
+		if o.qualified {
+			return "{{$.QuotedTable}}.{{.QuotedDBField}}"
+		}
+		return "{{.QuotedDBField}}"
 	}
 	func(o {{.ObjectName}}{{.GoName}}Order)Direction() string {
+		if o.nulls != "" {
+			return strings.TrimSpace(o.direction + " NULLS " + o.nulls)
+		}
 		return o.direction
 	}
 	func New{{.ObjectName}}{{.GoName}}Order() {{.ObjectName}}{{.GoName}}Order {
 		return {{.ObjectName}}{{.GoName}}Order{}
 	}
 	{{end}}
+
+	// Parse{{.Name}}Order parses a sort parameter such as "created_at:desc,name"
+	// into the orders of the columns. The columns must be columns of {{.Table}}
+	// and the directions asc or desc, optionally followed by nulls first or
+	// nulls last, e.g. "name:desc nulls last".
+	func Parse{{.Name}}Order(sort string) ([]Order, error) {
+		var orders []Order
+		for _, item := range strings.Split(sort, ",") {
+			item = strings.TrimSpace(item)
+			if item == "" {
+				continue
+			}
+
+			column, direction := item, ""
+			if i := strings.Index(item, ":"); i >= 0 {
+				column, direction = strings.TrimSpace(item[:i]), item[i+1:]
+			}
+			if _, _, err := parseOrderDirection(direction); err != nil {
+				return nil, err
+			}
+
+			switch column {
+			{{range .Fields}}case "{{.DBField}}":
+				orders = append(orders, New{{.ObjectName}}{{.GoName}}Order().SetDirection(direction))
+			{{end}}default:
+				return nil, fmt.Errorf("unknown {{.LowerName}} order column %q", column)
+			}
+		}
+		return orders, nil
+	}
+	`
+
+	templateCursor = `
+	{{if .HasPrimaryKey}}// Get{{.Name}}CursorList returns the page of {{.Table}} after the cursor
+	// and the cursor of the next page, empty on the last page. The rows are
+	// sorted by the orders of the repository, which must have the same
+	// direction, followed by the primary key.
+	func (repo *Repository{{.Name}}QueryImpl) Get{{.Name}}CursorList(ctx context.Context) ({{.ModelPackage}}{{.Name}}List, string, error) {
+		orders := repo.cursorOrders()
+		if len(orders) == 0 {
+			return nil, "", errors.New("{{.LowerName}} cursor pagination needs an order")
+		}
+
+		var (
+			direction string
+			columns   []string
+			fields    []{{.Name}}Field
+		)
+		for i, order := range orders {
+			orderDirection, nulls, err := parseOrderDirection(order.Direction())
+			if err != nil {
+				return nil, "", err
+			}
+			if nulls != "" || (i > 0 && orderDirection != direction) {
+				return nil, "", errors.New("{{.LowerName}} cursor pagination needs orders in the same direction without nulls ordering")
+			}
+			field, err := {{.PrivateName}}CursorField(order.Value())
+			if err != nil {
+				return nil, "", err
+			}
+			direction = orderDirection
+			columns = append(columns, order.Value())
+			fields = append(fields, field)
+		}
+
+		cloned := repo.clone()
+		cloned.orderBy = orders
+		cloned.pagination = nil
+		if repo.cursor.Cursor != "" {
+			rawValues, err := decodeCursor(repo.cursor.Cursor, len(fields))
+			if err != nil {
+				return nil, "", err
+			}
+			values := make([]interface{}, len(fields))
+			for i, field := range fields {
+				if values[i], err = decode{{.Name}}CursorValue(field, rawValues[i]); err != nil {
+					return nil, "", err
+				}
+			}
+
+			var after Filter = cursorFilter{columns: columns, operator: ">", values: values}
+			if direction == OrderDesc {
+				after = cursorFilter{columns: columns, operator: "{{.LessThan}}", values: values}
+			}
+			if repo.filter != nil {
+				after = And(repo.filter, after)
+			}
+			cloned.filter = after
+		}
+		if repo.cursor.Size > 0 {
+			cloned.pagination = PaginationData{Page: 1, Size: repo.cursor.Size + 1}
+		}
+		if len(repo.fields) > 0 {
+			cloned.fields = append({{.Name}}FieldList{}, repo.fields...)
+			for _, field := range fields {
+				if !cloned.fields.contains(field) {
+					cloned.fields = append(cloned.fields, field)
+				}
+			}
+		}
+
+		{{.PrivateName}}List, err := cloned.Get{{.Name}}List(ctx)
+		if err != nil {
+			return nil, "", err
+		}
+		var next string
+		if repo.cursor.Size > 0 && len({{.PrivateName}}List) > repo.cursor.Size {
+			{{.PrivateName}}List = {{.PrivateName}}List[:repo.cursor.Size]
+			last := {{.PrivateName}}List[len({{.PrivateName}}List)-1]
+			values := make([]interface{}, len(fields))
+			for i, field := range fields {
+				values[i] = {{.PrivateName}}CursorValue(last, field)
+			}
+			if next, err = encodeCursor(values); err != nil {
+				return nil, "", err
+			}
+		}
+		return {{.PrivateName}}List, next, nil
+	}
+
+	// cursorOrders returns the orders of the repository followed by the
+	// primary key, which breaks the ties between the rows.
+	func (repo *Repository{{.Name}}QueryImpl) cursorOrders() []Order {
+		orders := append([]Order{}, repo.orderBy...)
+		var direction string
+		if len(orders) > 0 {
+			direction = orders[0].Direction()
+		}
+		{{range .PrimaryKeys}}
+		{{.PrivateName}}Order := New{{.ObjectName}}{{.GoName}}Order().SetDirection(direction)
+		if !hasOrder(orders, {{.PrivateName}}Order) {
+			orders = append(orders, {{.PrivateName}}Order)
+		}{{end}}
+		return orders
+	}
+
+	func (fieldList {{.Name}}FieldList) contains(field {{.Name}}Field) bool {
+		for _, listed := range fieldList {
+			if listed == field {
+				return true
+			}
+		}
+		return false
+	}
+
+	// {{.PrivateName}}CursorField resolves the field of an ordered column, which
+	// must not be nullable since the NULL values could not be compared.
+	func {{.PrivateName}}CursorField(column string) ({{.Name}}Field, error) {
+		switch column {
+		{{range .Fields}}case "{{.QuotedDBField}}", "{{$.QuotedTable}}.{{.QuotedDBField}}":
+			{{if .Nullable}}return "", fmt.Errorf("{{$.LowerName}} cursor column %q is nullable", column){{else}}return {{.ObjectName}}Field("{{.DBField}}"), nil{{end}}
+		{{end}}}
+		return "", fmt.Errorf("unknown {{.LowerName}} cursor column %q", column)
+	}
+
+	func {{.PrivateName}}CursorValue({{.PrivateName}} *{{.ModelPackage}}{{.Name}}, field {{.Name}}Field) interface{} {
+		switch field {
+		{{range .Fields}}{{if not .Nullable}}case "{{.DBField}}":
+			return {{$.PrivateName}}.{{.GoName}}
+		{{end}}{{end}}}
+		return nil
+	}
+
+	func decode{{.Name}}CursorValue(field {{.Name}}Field, rawValue json.RawMessage) (interface{}, error) {
+		switch field {
+		{{range .Fields}}{{if not .Nullable}}case "{{.DBField}}":
+			var value {{.GoType}}
+			err := json.Unmarshal(rawValue, &value)
+			return value, err
+		{{end}}{{end}}}
+		return nil, fmt.Errorf("unknown {{.LowerName}} cursor field %q", field)
+	}
+	{{else}}
+	// Get{{.Name}}CursorList always fails since {{.Table}} has no primary key to
+	// break the ties between the rows of the cursor.
+	func (repo *Repository{{.Name}}QueryImpl) Get{{.Name}}CursorList(ctx context.Context) ({{.ModelPackage}}{{.Name}}List, string, error) {
+		return nil, "", errors.New("{{.LowerName}} cursor pagination needs a primary key")
+	}
+	{{end}}	`
+
+	templateAggregate = `
+	type Repository{{.Name}}GroupQuery interface {
+		{{range .Fields}}{{if .Numeric}}Sum{{.GoName}}() Repository{{.ObjectName}}GroupQuery
+		Avg{{.GoName}}() Repository{{.ObjectName}}GroupQuery
+		{{end}}{{if or .Numeric .Temporal}}Min{{.GoName}}() Repository{{.ObjectName}}GroupQuery
+		Max{{.GoName}}() Repository{{.ObjectName}}GroupQuery
+		{{end}}{{end}}Get{{.Name}}GroupList(ctx context.Context) ([]*{{.Name}}Group, error)
+	}
+
+	// {{.Name}}Group is a group of {{.Table}}, Key holds the grouped columns
+	// and the aggregates are only set when they are selected.
+	type {{.Name}}Group struct {
+		Key   *{{.ModelPackage}}{{.Name}}
+		Count int
+		{{range .Fields}}{{if .Numeric}}Sum{{.GoName}} {{.SumType}}
+		Avg{{.GoName}} float64
+		{{end}}{{if or .Numeric .Temporal}}Min{{.GoName}} *{{.BaseType}}
+		Max{{.GoName}} *{{.BaseType}}
+		{{end}}{{end}}
+	}
+
+	type {{.PrivateName}}Aggregate struct {
+		expression string
+		dest       func(group *{{.Name}}Group) interface{}
+	}
+
+	type Repository{{.Name}}GroupQueryImpl struct {
+		repo       *Repository{{.Name}}QueryImpl
+		fields     []{{.Name}}Field
+		aggregates []{{.PrivateName}}Aggregate
+	}
+
+	// GroupBy{{.Name}} groups the rows matching the filter by the fields, or
+	// aggregates every row without fields.
+	func (repo *Repository{{.Name}}QueryImpl) GroupBy{{.Name}}(fields ...{{.Name}}Field) Repository{{.Name}}GroupQuery {
+		return &Repository{{.Name}}GroupQueryImpl{
+			repo:   repo,
+			fields: fields,
+		}
+	}
+
+	func (group *Repository{{.Name}}GroupQueryImpl) aggregate(expression string, dest func(group *{{.Name}}Group) interface{}) Repository{{.Name}}GroupQuery {
+		cloned := *group
+		cloned.aggregates = append(append([]{{.PrivateName}}Aggregate{}, group.aggregates...), {{.PrivateName}}Aggregate{
+			expression: expression,
+			dest:       dest,
+		})
+		return &cloned
+	}
+
+	{{range .Fields}}{{if .Numeric}}func (group *Repository{{.ObjectName}}GroupQueryImpl) Sum{{.GoName}}() Repository{{.ObjectName}}GroupQuery {
+		return group.aggregate("COALESCE(SUM({{.QuotedDBField}}), 0)", func(group *{{.ObjectName}}Group) interface{} {
+			return &group.Sum{{.GoName}}
+		})
+	}
+
+	func (group *Repository{{.ObjectName}}GroupQueryImpl) Avg{{.GoName}}() Repository{{.ObjectName}}GroupQuery {
+		return group.aggregate("COALESCE(AVG({{.QuotedDBField}}), 0)", func(group *{{.ObjectName}}Group) interface{} {
+			return &group.Avg{{.GoName}}
+		})
+	}
+
+	{{end}}{{if or .Numeric .Temporal}}func (group *Repository{{.ObjectName}}GroupQueryImpl) Min{{.GoName}}() Repository{{.ObjectName}}GroupQuery {
+		return group.aggregate("MIN({{.QuotedDBField}})", func(group *{{.ObjectName}}Group) interface{} {
+			return {{if .Temporal}}timeScanner{time: &group.Min{{.GoName}}}{{else}}&group.Min{{.GoName}}{{end}}
+		})
+	}
+
+	func (group *Repository{{.ObjectName}}GroupQueryImpl) Max{{.GoName}}() Repository{{.ObjectName}}GroupQuery {
+		return group.aggregate("MAX({{.QuotedDBField}})", func(group *{{.ObjectName}}Group) interface{} {
+			return {{if .Temporal}}timeScanner{time: &group.Max{{.GoName}}}{{else}}&group.Max{{.GoName}}{{end}}
+		})
+	}
+
+	{{end}}{{end}}func (group *Repository{{.Name}}GroupQueryImpl) Get{{.Name}}GroupList(ctx context.Context) ([]*{{.Name}}Group, error) {
+		var (
+			columns []string
+			values  []interface{}
+		)
+		for _, field := range group.fields {
+			columns = append(columns, quoteIdentifier(string(field)))
+		}
+		groupBy := strings.Join(columns, ",")
+		columns = append(columns, "count(1)")
+		for _, aggregate := range group.aggregates {
+			columns = append(columns, aggregate.expression)
+		}
+
+		query := fmt.Sprintf("SELECT %s FROM {{.QuotedTable}}", strings.Join(columns, ","))
+		if group.repo.filter != nil {
+			if err := filterErr(group.repo.filter); err != nil {
+				return nil, err
+			}
+			if filterQuery := group.repo.filter.Query(); filterQuery != "" {
+				query += " WHERE " + filterQuery
+				values = append(values, group.repo.filter.Values()...)
+			}
+		}
+		if groupBy != "" {
+			query += " GROUP BY " + groupBy + " ORDER BY " + groupBy
+		}
+
+		rows, err := group.repo.db.QueryContext(ctx, rebind(query), values...)
+		if err != nil {
+			return nil, err
+		}
+		defer rows.Close()
+
+		var groups []*{{.Name}}Group
+		for rows.Next() {
+			{{.PrivateName}}Group := &{{.Name}}Group{Key: &{{.ModelPackage}}{{.Name}}{}}
+			var dest []interface{}
+			for _, field := range group.fields {
+				fieldDest := {{.PrivateName}}FieldDest({{.PrivateName}}Group.Key, field)
+				if fieldDest == nil {
+					return nil, fmt.Errorf("unknown {{.LowerName}} field %q", field)
+				}
+				dest = append(dest, fieldDest)
+			}
+			dest = append(dest, &{{.PrivateName}}Group.Count)
+			for _, aggregate := range group.aggregates {
+				dest = append(dest, aggregate.dest({{.PrivateName}}Group))
+			}
+
+			if err := rows.Scan(dest...); err != nil {
+				return nil, err
+			}
+			groups = append(groups, {{.PrivateName}}Group)
+		}
+		return groups, rows.Err()
+	}
+
+	func {{.PrivateName}}FieldDest({{.PrivateName}} *{{.ModelPackage}}{{.Name}}, field {{.Name}}Field) interface{} {
+		switch field {
+		{{range .Fields}}case "{{.DBField}}":
+			return &{{$.PrivateName}}.{{.GoName}}
+		{{end}}}
+		return nil
+	}
+
+	{{range .Fields}}{{if .Numeric}}func (repo *Repository{{.ObjectName}}QueryImpl) Sum{{.ObjectName}}{{.GoName}}(ctx context.Context) ({{.SumType}}, error) {
+		var sum {{.SumType}}
+		groups, err := repo.GroupBy{{.ObjectName}}().Sum{{.GoName}}().Get{{.ObjectName}}GroupList(ctx)
+		if err != nil || len(groups) == 0 {
+			return sum, err
+		}
+		return groups[0].Sum{{.GoName}}, nil
+	}
+
+	func (repo *Repository{{.ObjectName}}QueryImpl) Avg{{.ObjectName}}{{.GoName}}(ctx context.Context) (float64, error) {
+		groups, err := repo.GroupBy{{.ObjectName}}().Avg{{.GoName}}().Get{{.ObjectName}}GroupList(ctx)
+		if err != nil || len(groups) == 0 {
+			return 0, err
+		}
+		return groups[0].Avg{{.GoName}}, nil
+	}
+
+	{{end}}{{if or .Numeric .Temporal}}func (repo *Repository{{.ObjectName}}QueryImpl) Min{{.ObjectName}}{{.GoName}}(ctx context.Context) (*{{.BaseType}}, error) {
+		groups, err := repo.GroupBy{{.ObjectName}}().Min{{.GoName}}().Get{{.ObjectName}}GroupList(ctx)
+		if err != nil || len(groups) == 0 {
+			return nil, err
+		}
+		return groups[0].Min{{.GoName}}, nil
+	}
+
+	func (repo *Repository{{.ObjectName}}QueryImpl) Max{{.ObjectName}}{{.GoName}}(ctx context.Context) (*{{.BaseType}}, error) {
+		groups, err := repo.GroupBy{{.ObjectName}}().Max{{.GoName}}().Get{{.ObjectName}}GroupList(ctx)
+		if err != nil || len(groups) == 0 {
+			return nil, err
+		}
+		return groups[0].Max{{.GoName}}, nil
+	}
+
+	{{end}}{{end}}`
+
+	templateJoin = `
+	{{range .Relations}}{{if ne .Related.Table $.Table}}
+	type {{$.Name}}{{.Name}}Join struct {
+		{{$.Name}} *{{$.ModelPackage}}{{$.Name}} {{$.Backtick}}db:"{{$.Table}}"{{$.Backtick}}
+		{{.Name}} *{{$.ModelPackage}}{{.Related.Name}} {{$.Backtick}}db:"{{.Related.Table}}"{{$.Backtick}}
+	}
+
+	type {{$.Name}}{{.Name}}JoinList []*{{$.Name}}{{.Name}}Join
+
+	type Repository{{$.Name}}{{.Name}}JoinQuery interface {
+		Select{{$.Name}}(fields ...{{$.Name}}Field) Repository{{$.Name}}{{.Name}}JoinQuery
+		Select{{.Name}}(fields ...{{.Related.Name}}Field) Repository{{$.Name}}{{.Name}}JoinQuery
+		Filter{{$.Name}}{{.Name}}Join(filter Filter) Repository{{$.Name}}{{.Name}}JoinQuery
+		Pagination{{$.Name}}{{.Name}}Join(pagination Pagination) Repository{{$.Name}}{{.Name}}JoinQuery
+		OrderBy{{$.Name}}{{.Name}}Join(orderBy []Order) Repository{{$.Name}}{{.Name}}JoinQuery
+		Get{{$.Name}}{{.Name}}JoinCount(ctx context.Context) (int, error)
+		Get{{$.Name}}{{.Name}}JoinList(ctx context.Context) ({{$.Name}}{{.Name}}JoinList, error)
+	}
+
+	type Repository{{$.Name}}{{.Name}}JoinQueryImpl struct {
+		db            DBTX
+		filter        Filter
+		orderBy       []Order
+		pagination    Pagination
+		fields        {{$.Name}}FieldList
+		relatedFields {{.Related.Name}}FieldList
+	}
+
+	func (repo *Repository{{$.Name}}QueryImpl) Join{{.Name}}() Repository{{$.Name}}{{.Name}}JoinQuery {
+		return &Repository{{$.Name}}{{.Name}}JoinQueryImpl{
+			db: repo.db,
+		}
+	}
+
+	func (repo *Repository{{$.Name}}{{.Name}}JoinQueryImpl) Select{{$.Name}}(fields ...{{$.Name}}Field) Repository{{$.Name}}{{.Name}}JoinQuery {
+		cloned := *repo
+		cloned.fields = fields
+		return &cloned
+	}
+
+	func (repo *Repository{{$.Name}}{{.Name}}JoinQueryImpl) Select{{.Name}}(fields ...{{.Related.Name}}Field) Repository{{$.Name}}{{.Name}}JoinQuery {
+		cloned := *repo
+		cloned.relatedFields = fields
+		return &cloned
+	}
+
+	func (repo *Repository{{$.Name}}{{.Name}}JoinQueryImpl) Filter{{$.Name}}{{.Name}}Join(filter Filter) Repository{{$.Name}}{{.Name}}JoinQuery {
+		cloned := *repo
+		cloned.filter = filter
+		return &cloned
+	}
+
+	func (repo *Repository{{$.Name}}{{.Name}}JoinQueryImpl) Pagination{{$.Name}}{{.Name}}Join(pagination Pagination) Repository{{$.Name}}{{.Name}}JoinQuery {
+		cloned := *repo
+		cloned.pagination = pagination
+		return &cloned
+	}
+
+	func (repo *Repository{{$.Name}}{{.Name}}JoinQueryImpl) OrderBy{{$.Name}}{{.Name}}Join(orderBy []Order) Repository{{$.Name}}{{.Name}}JoinQuery {
+		cloned := *repo
+		cloned.orderBy = orderBy
+		return &cloned
+	}
+
+	func (repo *Repository{{$.Name}}{{.Name}}JoinQueryImpl) Get{{$.Name}}{{.Name}}JoinList(ctx context.Context) ({{$.Name}}{{.Name}}JoinList, error) {
+		var (
+			joinList {{$.Name}}{{.Name}}JoinList
+			values   []interface{}
+		)
+
+		fields := repo.fields
+		if len(fields) == 0 {
+			fields = {{$.Name}}SelectFields{}.All()
+		}
+		relatedFields := repo.relatedFields
+		if len(relatedFields) == 0 {
+			relatedFields = {{.Related.Name}}SelectFields{}.All()
+		}
+
+		var columns []string
+		for _, field := range fields.toString() {
+			columns = append(columns, "{{$.QuotedTable}}."+quoteIdentifier(field)+" AS "+quoteIdentifier("{{$.Table}}."+field))
+		}
+		for _, field := range relatedFields.toString() {
+			columns = append(columns, "{{.Related.QuotedTable}}."+quoteIdentifier(field)+" AS "+quoteIdentifier("{{.Related.Table}}."+field))
+		}
+
+		query := fmt.Sprintf("SELECT %s FROM {{$.QuotedTable}} JOIN {{.Related.QuotedTable}} ON {{$.QuotedTable}}.{{.Field.QuotedDBField}} = {{.Related.QuotedTable}}.{{.RelatedField.QuotedDBField}}",
+			strings.Join(columns, ","))
+		if repo.filter != nil {
+			if err := filterErr(repo.filter); err != nil {
+				return nil, err
+			}
+			if filterQuery := repo.filter.Query(); filterQuery != "" {
+				query += " WHERE " + filterQuery
+				values = append(values, repo.filter.Values()...)
+			}
+		}
+
+		if len(repo.orderBy) > 0 {
+			orderBy, err := orderByClause(repo.orderBy)
+			if err != nil {
+				return nil, err
+			}
+			query += " ORDER BY " + orderBy
+		}
+
+		if repo.pagination != nil {
+			offset := (repo.pagination.GetPage() - 1) * repo.pagination.GetSize()
+			query += fmt.Sprintf(" LIMIT %d OFFSET %d", repo.pagination.GetSize(), offset)
+		}
+
+		err := repo.db.SelectContext(ctx, &joinList, rebind(query), values...)
+		if err != nil {
+			return nil, err
+		}
+		return joinList, nil
+	}
+
+	func (repo *Repository{{$.Name}}{{.Name}}JoinQueryImpl) Get{{$.Name}}{{.Name}}JoinCount(ctx context.Context) (int, error) {
+		var values []interface{}
+		query := "SELECT count(1) FROM {{$.QuotedTable}} JOIN {{.Related.QuotedTable}} ON {{$.QuotedTable}}.{{.Field.QuotedDBField}} = {{.Related.QuotedTable}}.{{.RelatedField.QuotedDBField}}"
+		if repo.filter != nil {
+			if err := filterErr(repo.filter); err != nil {
+				return 0, err
+			}
+			if filterQuery := repo.filter.Query(); filterQuery != "" {
+				query += " WHERE " + filterQuery
+				values = append(values, repo.filter.Values()...)
+			}
+		}
+
+		var count int
+		err := repo.db.QueryRowContext(ctx, rebind(query), values...).Scan(&count)
+		return count, err
+	}
+	{{end}}{{end}}
 	`
 )
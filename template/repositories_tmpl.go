@@ -0,0 +1,70 @@
+package template
+
+func (tp *TemplateParser) ParseRepositoriesTmpl() (string, error) {
+	return tp.execTmpl(`
+	// Repositories holds the repositories of every table, bound to the same
+	// database or transaction.
+	type Repositories struct {
+		db   DBTX
+		inTx bool
+		{{range .Objects}}{{.Name}}Query Repository{{.Name}}Query
+		{{if not $.QueryOnly}}{{.Name}}Command Repository{{.Name}}Command
+		{{end}}{{end}}
+	}
+
+	func NewRepositories(db DBTX) *Repositories {
+		_, inTx := db.(*sqlx.Tx)
+		return &Repositories{
+			db:   db,
+			inTx: inTx,
+			{{range .Objects}}{{.Name}}Query: NewRepo{{.Name}}Query(db),
+			{{if not $.QueryOnly}}{{.Name}}Command: NewRepo{{.Name}}Command(db),
+			{{end}}{{end}}
+		}
+	}
+
+	func NewRepositoriesFromTx(tx DBTX) *Repositories {
+		return &Repositories{
+			db:   tx,
+			inTx: true,
+			{{range .Objects}}{{.Name}}Query: NewRepo{{.Name}}QueryFromTx(tx),
+			{{if not $.QueryOnly}}{{.Name}}Command: NewRepo{{.Name}}CommandFromTx(tx),
+			{{end}}{{end}}
+		}
+	}
+
+	type txBeginner interface {
+		BeginTxx(ctx context.Context, opts *sql.TxOptions) (*sqlx.Tx, error)
+	}
+
+	// RunInTx calls fn with the repositories bound to a new transaction, which
+	// is committed when fn succeeds and rolled back when fn returns an error or
+	// panics. The repositories already bound to a transaction run fn within it.
+	func (r *Repositories) RunInTx(ctx context.Context, opts *sql.TxOptions, fn func(r *Repositories) error) error {
+		if r.inTx {
+			return fn(r)
+		}
+
+		db, ok := r.db.(txBeginner)
+		if !ok {
+			return errors.New("repositories: the database could not begin a transaction")
+		}
+		tx, err := db.BeginTxx(ctx, opts)
+		if err != nil {
+			return err
+		}
+		defer func() {
+			if p := recover(); p != nil {
+				tx.Rollback()
+				panic(p)
+			}
+		}()
+
+		if err := fn(NewRepositoriesFromTx(tx)); err != nil {
+			tx.Rollback()
+			return err
+		}
+		return tx.Commit()
+	}
+	`)
+}
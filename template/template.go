@@ -9,23 +9,44 @@ import (
 
 type TemplateParser struct {
 	Object       *parser.Object
+	Objects      []*parser.Object
 	ModelPackage string
+	Dialect      string
+	QueryOnly    bool
 }
 
 func (tp *TemplateParser) execTmpl(s string) (string, error) {
 	var data struct {
 		*parser.Object
-		Backtick     string
-		OpenBracket  string
-		CloseBracket string
-		ModelPackage string
+		Objects         []*parser.Object
+		QueryOnly       bool
+		Backtick        string
+		OpenBracket     string
+		CloseBracket    string
+		LessThan        template.HTML
+		ModelPackage    string
+		Dialect         string
+		BindType        string
+		IdentifierQuote template.HTML
 	}
 
 	data.Object = tp.Object
+	data.Objects = tp.Objects
+	data.QueryOnly = tp.QueryOnly
 	data.Backtick = "`"
 	data.OpenBracket = "{"
 	data.CloseBracket = "}"
+	data.LessThan = "<"
 	data.ModelPackage = tp.ModelPackage
+	data.Dialect = tp.Dialect
+	data.BindType = "QUESTION"
+	if tp.Dialect == parser.DialectPostgres {
+		data.BindType = "DOLLAR"
+	}
+	data.IdentifierQuote = `"\""`
+	if tp.Dialect == parser.DialectMySQL {
+		data.IdentifierQuote = "\"`\""
+	}
 	return execTmpl(s, data)
 }
 